@@ -0,0 +1,227 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+
+	"github.com/salmomascarenhas/go-study-exercises/errors/errtree"
+	"github.com/salmomascarenhas/go-study-exercises/errors/retry"
+)
+
+// Code classifies an AppError. Codes are stable strings, safe to send to
+// clients and to match on.
+type Code string
+
+const (
+	CodeUnknown            Code = "UNKNOWN"
+	CodeInvalidArgument    Code = "INVALID_ARGUMENT"
+	CodeNotFound           Code = "NOT_FOUND"
+	CodeAlreadyExists      Code = "ALREADY_EXISTS"
+	CodePermissionDenied   Code = "PERMISSION_DENIED"
+	CodeUnauthenticated    Code = "UNAUTHENTICATED"
+	CodeFailedPrecondition Code = "FAILED_PRECONDITION"
+	CodeDeadlineExceeded   Code = "DEADLINE_EXCEEDED"
+	CodeUnavailable        Code = "UNAVAILABLE"
+	CodeInternal           Code = "INTERNAL"
+)
+
+// AppError is the structured error of the application.
+//
+// Message is written for users and never includes internal details; Cause
+// holds those, and is only part of Error(), which is meant for logs.
+type AppError struct {
+	Code    Code
+	Message string
+	Cause   error
+	Meta    map[string]any
+	// Key and Args translate Message through a Localizer; see i18n.go.
+	Key  string
+	Args []any
+}
+
+// NewAppError returns an AppError without a cause.
+func NewAppError(code Code, message string) *AppError {
+	return &AppError{Code: code, Message: message}
+}
+
+// WrapAppError returns an AppError caused by cause.
+func WrapAppError(cause error, code Code, message string) *AppError {
+	return &AppError{Code: code, Message: message, Cause: cause}
+}
+
+func (e *AppError) Error() string {
+	if e.Cause == nil {
+		return e.Message
+	}
+	return e.Message + ": " + e.Cause.Error()
+}
+
+func (e *AppError) Unwrap() error { return e.Cause }
+
+// Format implements fmt.Formatter like the errors of package stacktrace:
+// %+v prints the message followed by the cause formatted with %+v, so the
+// stack traces under an AppError are not lost. Other verbs print Error().
+func (e *AppError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') && e.Cause != nil {
+			fmt.Fprintf(s, "%s: %+v", e.Message, e.Cause)
+			return
+		}
+		io.WriteString(s, e.Error())
+	case 's':
+		io.WriteString(s, e.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	}
+}
+
+// Is makes errors.Is match any AppError with the same code, so that
+// errors.Is(err, ErrorNotFound) holds for every NOT_FOUND error, whatever
+// its message.
+func (e *AppError) Is(target error) bool {
+	t, ok := target.(*AppError)
+	return ok && t.Code == e.Code
+}
+
+// WithMeta returns a copy of e with key set to value in its metadata. It
+// copies so that sentinel errors are never modified.
+func (e *AppError) WithMeta(key string, value any) *AppError {
+	c := *e
+	c.Meta = maps.Clone(e.Meta)
+	if c.Meta == nil {
+		c.Meta = make(map[string]any)
+	}
+	c.Meta[key] = value
+	return &c
+}
+
+// WithCause returns a copy of e caused by cause.
+func (e *AppError) WithCause(cause error) *AppError {
+	c := *e
+	c.Cause = cause
+	return &c
+}
+
+// WithKey returns a copy of e whose message is translated by key and args.
+func (e *AppError) WithKey(key string, args ...any) *AppError {
+	c := *e
+	c.Key = key
+	c.Args = args
+	return &c
+}
+
+func (e *AppError) MessageKey() (string, []any) { return e.Key, e.Args }
+
+// Coder is implemented by errors that carry a Code without being an
+// AppError, such as SqrtError.
+type Coder interface {
+	Code() Code
+}
+
+// CodeOf returns the code of the first AppError or Coder in err's tree, ""
+// for a nil error and CodeUnknown for errors that carry no code.
+func CodeOf(err error) Code {
+	if err == nil {
+		return ""
+	}
+	code := CodeUnknown
+	errtree.Walk(err, func(_ string, e error) bool {
+		if code != CodeUnknown {
+			return false
+		}
+		switch e := e.(type) { //errlint:ignore Walk visits each node
+		case *AppError:
+			code = e.Code
+		case Coder:
+			code = e.Code()
+		}
+		return code == CodeUnknown
+	})
+	return code
+}
+
+// SafeMessage returns a message fit for users: the Message of the first
+// AppError in err's tree, or a generic one when there is none.
+func SafeMessage(err error) string {
+	var appErr *AppError
+	if errors.As(err, &appErr) && appErr.Message != "" {
+		return appErr.Message
+	}
+	return "internal error"
+}
+
+// HTTPStatus maps a code to its HTTP status.
+func HTTPStatus(code Code) int {
+	switch code {
+	case "":
+		return http.StatusOK
+	case CodeInvalidArgument:
+		return http.StatusBadRequest
+	case CodeNotFound:
+		return http.StatusNotFound
+	case CodeAlreadyExists:
+		return http.StatusConflict
+	case CodePermissionDenied:
+		return http.StatusForbidden
+	case CodeUnauthenticated:
+		return http.StatusUnauthorized
+	case CodeFailedPrecondition:
+		return http.StatusPreconditionFailed
+	case CodeDeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case CodeUnavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// ExitCode maps a code to a process exit code, following the BSD
+// sysexits.h conventions.
+func ExitCode(code Code) int {
+	switch code {
+	case "":
+		return 0
+	case CodeInvalidArgument:
+		return 64 // EX_USAGE
+	case CodeNotFound:
+		return 66 // EX_NOINPUT
+	case CodeAlreadyExists:
+		return 73 // EX_CANTCREAT
+	case CodePermissionDenied, CodeUnauthenticated:
+		return 77 // EX_NOPERM
+	case CodeFailedPrecondition:
+		return 65 // EX_DATAERR
+	case CodeDeadlineExceeded:
+		return 75 // EX_TEMPFAIL
+	case CodeUnavailable:
+		return 69 // EX_UNAVAILABLE
+	case CodeInternal:
+		return 70 // EX_SOFTWARE
+	default:
+		return 1
+	}
+}
+
+// HTTPStatusOf maps err to an HTTP status through its code.
+func HTTPStatusOf(err error) int { return HTTPStatus(CodeOf(err)) }
+
+// ExitCodeOf maps err to a process exit code through its code.
+func ExitCodeOf(err error) int { return ExitCode(CodeOf(err)) }
+
+// Retrying doesn't change the outcome of an invalid argument, a missing or
+// a duplicate resource, so retry.DefaultClassifier gives up on those codes
+// after the first attempt.
+func init() {
+	retry.DefaultClassifier.RegisterFunc(func(err error) bool {
+		switch CodeOf(err) {
+		case CodeInvalidArgument, CodeNotFound, CodeAlreadyExists:
+			return true
+		}
+		return false
+	}, retry.Permanent)
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestCodeMappings(t *testing.T) {
+	tests := []struct {
+		code       Code
+		httpStatus int
+		exitCode   int
+	}{
+		{"", http.StatusOK, 0},
+		{CodeUnknown, http.StatusInternalServerError, 1},
+		{CodeInvalidArgument, http.StatusBadRequest, 64},
+		{CodeNotFound, http.StatusNotFound, 66},
+		{CodeAlreadyExists, http.StatusConflict, 73},
+		{CodePermissionDenied, http.StatusForbidden, 77},
+		{CodeUnauthenticated, http.StatusUnauthorized, 77},
+		{CodeFailedPrecondition, http.StatusPreconditionFailed, 65},
+		{CodeDeadlineExceeded, http.StatusGatewayTimeout, 75},
+		{CodeUnavailable, http.StatusServiceUnavailable, 69},
+		{CodeInternal, http.StatusInternalServerError, 70},
+		{"SOMETHING_NEW", http.StatusInternalServerError, 1},
+	}
+	for _, tt := range tests {
+		if got := HTTPStatus(tt.code); got != tt.httpStatus {
+			t.Errorf("HTTPStatus(%q) = %d, want %d", tt.code, got, tt.httpStatus)
+		}
+		if got := ExitCode(tt.code); got != tt.exitCode {
+			t.Errorf("ExitCode(%q) = %d, want %d", tt.code, got, tt.exitCode)
+		}
+	}
+}
+
+func TestCodeOf(t *testing.T) {
+	_, sqrtErr := raizQuadrada(-4)
+	tests := []struct {
+		name string
+		err  error
+		want Code
+		safe string
+	}{
+		{"nil", nil, "", "internal error"},
+		{"plain", errors.New("boom"), CodeUnknown, "internal error"},
+		{"AppError", ErrorNotFound, CodeNotFound, "not found"},
+		{"wrapped AppError", fmt.Errorf("get user: %w", ErrorNotFound), CodeNotFound, "not found"},
+		{"SqrtError", sqrtErr, CodeInvalidArgument, "internal error"},
+		{"wrapped SqrtError", fmt.Errorf("calculando: %w", sqrtErr), CodeInvalidArgument, "internal error"},
+		{"outermost wins", WrapAppError(sqrtErr, CodeInternal, "boom"), CodeInternal, "boom"},
+		{"first in a join", errors.Join(errors.New("plain"), sqrtErr, ErrorNotFound), CodeInvalidArgument, "not found"},
+		{"empty message", NewAppError(CodeUnavailable, ""), CodeUnavailable, "internal error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CodeOf(tt.err); got != tt.want {
+				t.Errorf("CodeOf = %q, want %q", got, tt.want)
+			}
+			if got := SafeMessage(tt.err); got != tt.safe {
+				t.Errorf("SafeMessage = %q, want %q", got, tt.safe)
+			}
+			if got, want := HTTPStatusOf(tt.err), HTTPStatus(tt.want); got != want {
+				t.Errorf("HTTPStatusOf = %d, want %d", got, want)
+			}
+			if got, want := ExitCodeOf(tt.err), ExitCode(tt.want); got != want {
+				t.Errorf("ExitCodeOf = %d, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestAppErrorIsByCode(t *testing.T) {
+	_, sqrtErr := raizQuadrada(-4)
+	_, userErr := NewUser(WithAge(-1))
+	tests := []struct {
+		name   string
+		err    error
+		target error
+		want   bool
+	}{
+		{"same code, other message", NewAppError(CodeNotFound, "no such user"), ErrorNotFound, true},
+		{"wrapped", fmt.Errorf("get: %w", NewAppError(CodeNotFound, "x")), ErrorNotFound, true},
+		{"other code", NewAppError(CodeInternal, "not found"), ErrorNotFound, false},
+		{"plain error", errors.New("not found"), ErrorNotFound, false},
+		{"SqrtError isn't an AppError", sqrtErr, NewAppError(CodeInvalidArgument, "x"), false},
+		{"SqrtError isn't a NewUser failure", sqrtErr, userErr, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.Is(tt.err, tt.target); got != tt.want {
+				t.Errorf("errors.Is(%v, %v) = %t, want %t", tt.err, tt.target, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppErrorCopies(t *testing.T) {
+	base := NewAppError(CodeNotFound, "not found").WithMeta("a", 1)
+	withB := base.WithMeta("b", 2)
+	if _, ok := base.Meta["b"]; ok {
+		t.Errorf("WithMeta changed the original's Meta: %v", base.Meta)
+	}
+	if withB.Meta["a"] != 1 || withB.Meta["b"] != 2 {
+		t.Errorf("copy Meta = %v, want a and b", withB.Meta)
+	}
+	withB.Meta["a"] = 3
+	if base.Meta["a"] != 1 {
+		t.Errorf("the copy shares its Meta with the original: %v", base.Meta)
+	}
+
+	// Sentinels stay as declared, whatever is derived from them.
+	_ = ErrorNotFound.WithMeta("id", 42).WithCause(errors.New("db")).WithKey("other")
+	if ErrorNotFound.Meta != nil || ErrorNotFound.Cause != nil || ErrorNotFound.Key != "not_found" {
+		t.Errorf("deriving from ErrorNotFound changed it: %+v", *ErrorNotFound)
+	}
+}
@@ -0,0 +1,18 @@
+// Command errlint runs the errlint analyzer. errlint is a module of its
+// own, so that only it needs the Go version golang.org/x/tools requires;
+// install it from there and run it from the repository root:
+//
+//	(cd errors/errlint && go install ./cmd/errlint)
+//	errlint ./...
+//
+// It accepts the flags of every go/analysis single checker; -fix is not
+// supported, since errlint suggests no fixes.
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/salmomascarenhas/go-study-exercises/errors/errlint"
+)
+
+func main() { singlechecker.Main(errlint.Analyzer) }
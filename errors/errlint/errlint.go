@@ -0,0 +1,277 @@
+// Package errlint defines an analyzer that checks that errors are wrapped
+// and inspected the way Go 1.13+ expects, so that errors.Is and errors.As
+// keep working through every layer. It reports:
+//
+//   - fmt.Errorf calls that format an error with %v or %s, which flattens
+//     it into text, instead of wrapping it with %w;
+//   - == and != comparisons with a sentinel error (a package-level variable
+//     of error type, such as io.EOF), which miss wrapped sentinels, instead
+//     of errors.Is;
+//   - type assertions and type switches on an error, which miss wrapped
+//     errors, instead of errors.As.
+//
+// Comparisons and assertions inside an Is(error) bool method are allowed,
+// since that is where a type says which errors it matches, and so are
+// assertions to interfaces with an Unwrap method, since they walk the tree
+// itself. Code that inspects one error of a tree at a time on purpose can
+// silence a report with an //errlint:ignore comment on the same line or
+// the line above.
+package errlint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "errlint",
+	Doc:      "report errors wrapped with %v instead of %w, and compared or asserted instead of errors.Is and errors.As",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+var errorType = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+
+func run(pass *analysis.Pass) (any, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	r := newReporter(pass)
+	nodes := []ast.Node{
+		(*ast.CallExpr)(nil),
+		(*ast.BinaryExpr)(nil),
+		(*ast.TypeAssertExpr)(nil),
+	}
+	insp.WithStack(nodes, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			checkErrorf(r, n)
+		case *ast.BinaryExpr:
+			if !inIsMethod(pass, stack) {
+				checkComparison(r, n)
+			}
+		case *ast.TypeAssertExpr:
+			if !inIsMethod(pass, stack) {
+				checkAssertion(r, n, stack)
+			}
+		}
+		return true
+	})
+	return nil, nil
+}
+
+// ignoreDirective silences the reports on its line and the next one.
+const ignoreDirective = "//errlint:ignore"
+
+// reporter reports diagnostics, except where an ignoreDirective says not
+// to.
+type reporter struct {
+	*analysis.Pass
+	// ignored holds the lines, as "filename:line", silenced by a directive.
+	ignored map[string]bool
+}
+
+func newReporter(pass *analysis.Pass) *reporter {
+	r := &reporter{Pass: pass, ignored: make(map[string]bool)}
+	for _, file := range pass.Files {
+		for _, group := range file.Comments {
+			for _, c := range group.List {
+				if !strings.HasPrefix(c.Text, ignoreDirective) {
+					continue
+				}
+				pos := pass.Fset.Position(c.Slash)
+				r.ignored[fmt.Sprintf("%s:%d", pos.Filename, pos.Line)] = true
+				r.ignored[fmt.Sprintf("%s:%d", pos.Filename, pos.Line+1)] = true
+			}
+		}
+	}
+	return r
+}
+
+func (r *reporter) Reportf(pos token.Pos, format string, args ...any) {
+	p := r.Fset.Position(pos)
+	if !r.ignored[fmt.Sprintf("%s:%d", p.Filename, p.Line)] {
+		r.Pass.Reportf(pos, format, args...)
+	}
+}
+
+func checkErrorf(r *reporter, call *ast.CallExpr) {
+	if !isFunc(r, call.Fun, "fmt.Errorf") || len(call.Args) < 2 || call.Ellipsis.IsValid() {
+		return
+	}
+	format := r.TypesInfo.Types[call.Args[0]].Value
+	if format == nil || format.Kind() != constant.String {
+		return
+	}
+	verbs, ok := formatVerbs(constant.StringVal(format))
+	if !ok {
+		return
+	}
+	args := call.Args[1:]
+	for i, verb := range verbs {
+		if i >= len(args) {
+			break
+		}
+		if (verb == 'v' || verb == 's') && isError(r.TypesInfo.TypeOf(args[i])) {
+			r.Reportf(args[i].Pos(), "fmt.Errorf formats an error with %%%c; use %%w to wrap it", verb)
+		}
+	}
+}
+
+// formatVerbs returns the verb that consumes each argument of format, in
+// order, with '*' for a width or precision read from the arguments. It
+// returns false for explicit argument indexes like %[1]v, which it doesn't
+// follow.
+func formatVerbs(format string) ([]rune, bool) {
+	var verbs []rune
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		// Skip flags, width and precision.
+		for i++; i < len(format) && strings.IndexByte("+-# 0123456789.*[", format[i]) >= 0; i++ {
+			switch format[i] {
+			case '[':
+				return nil, false
+			case '*':
+				verbs = append(verbs, '*')
+			}
+		}
+		if i == len(format) {
+			break
+		}
+		verb, size := utf8.DecodeRuneInString(format[i:])
+		if verb != '%' {
+			verbs = append(verbs, verb)
+		}
+		i += size - 1
+	}
+	return verbs, true
+}
+
+func checkComparison(r *reporter, expr *ast.BinaryExpr) {
+	if expr.Op != token.EQL && expr.Op != token.NEQ {
+		return
+	}
+	for _, pair := range [][2]ast.Expr{{expr.X, expr.Y}, {expr.Y, expr.X}} {
+		value, sentinel := pair[0], sentinelVar(r, pair[1])
+		if sentinel != nil && isError(r.TypesInfo.TypeOf(value)) {
+			r.Reportf(expr.Pos(), "comparison with sentinel error %s misses wrapped errors; use errors.Is", sentinel.Name())
+			return
+		}
+	}
+}
+
+// sentinelVar returns the package-level error variable expr refers to, or
+// nil.
+func sentinelVar(r *reporter, expr ast.Expr) *types.Var {
+	var id *ast.Ident
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		id = e
+	case *ast.SelectorExpr:
+		id = e.Sel
+	default:
+		return nil
+	}
+	v, ok := r.TypesInfo.Uses[id].(*types.Var)
+	if !ok || v.Pkg() == nil || v.Parent() != v.Pkg().Scope() || !isError(v.Type()) {
+		return nil
+	}
+	return v
+}
+
+func checkAssertion(r *reporter, expr *ast.TypeAssertExpr, stack []ast.Node) {
+	if !types.Identical(r.TypesInfo.TypeOf(expr.X), types.Universe.Lookup("error").Type()) {
+		return
+	}
+	if expr.Type != nil {
+		if !isUnwrapper(r.TypesInfo.TypeOf(expr.Type)) {
+			r.Reportf(expr.Pos(), "type assertion on an error misses wrapped errors; use errors.As")
+		}
+		return
+	}
+
+	// expr is the x.(type) of a type switch, two levels up.
+	if len(stack) < 3 {
+		return
+	}
+	sw, ok := stack[len(stack)-3].(*ast.TypeSwitchStmt)
+	if !ok {
+		return
+	}
+	for _, stmt := range sw.Body.List {
+		for _, typ := range stmt.(*ast.CaseClause).List {
+			if !isUnwrapper(r.TypesInfo.TypeOf(typ)) {
+				r.Reportf(sw.Pos(), "type switch on an error misses wrapped errors; use errors.As")
+				return
+			}
+		}
+	}
+}
+
+// isUnwrapper reports whether t is an interface with an Unwrap method, the
+// kind of assertion that walks an error tree rather than searching it.
+func isUnwrapper(t types.Type) bool {
+	iface, ok := t.Underlying().(*types.Interface)
+	if !ok {
+		return false
+	}
+	for i := 0; i < iface.NumMethods(); i++ {
+		if iface.Method(i).Name() == "Unwrap" {
+			return true
+		}
+	}
+	return false
+}
+
+// inIsMethod reports whether the innermost function in stack is an
+// Is(error) bool method.
+func inIsMethod(pass *analysis.Pass, stack []ast.Node) bool {
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch fn := stack[i].(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.FuncDecl:
+			if fn.Recv == nil || fn.Name.Name != "Is" {
+				return false
+			}
+			sig := pass.TypesInfo.Defs[fn.Name].Type().(*types.Signature)
+			return sig.Params().Len() == 1 && isError(sig.Params().At(0).Type()) &&
+				sig.Results().Len() == 1 && types.Identical(sig.Results().At(0).Type(), types.Typ[types.Bool])
+		}
+	}
+	return false
+}
+
+// isFunc reports whether fun refers to the function with the given full
+// name, such as "fmt.Errorf".
+func isFunc(r *reporter, fun ast.Expr, name string) bool {
+	sel, ok := ast.Unparen(fun).(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	fn, ok := r.TypesInfo.Uses[sel.Sel].(*types.Func)
+	return ok && fn.FullName() == name
+}
+
+// isError reports whether t implements error. Untyped nil doesn't.
+func isError(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	if b, ok := t.(*types.Basic); ok && b.Kind() == types.UntypedNil {
+		return false
+	}
+	return types.Implements(t, errorType)
+}
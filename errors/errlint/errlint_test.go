@@ -0,0 +1,13 @@
+package errlint_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/salmomascarenhas/go-study-exercises/errors/errlint"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), errlint.Analyzer, "errorf", "sentinel", "assertion", "ignore")
+}
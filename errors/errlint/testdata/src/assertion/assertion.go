@@ -0,0 +1,47 @@
+package assertion
+
+import (
+	"errors"
+	"io/fs"
+)
+
+type codeError struct{ code int }
+
+func (e *codeError) Error() string { return "code error" }
+
+// Is may assert on target: it is what errors.Is calls.
+func (e *codeError) Is(target error) bool {
+	t, ok := target.(*codeError)
+	return ok && t.code == e.code
+}
+
+func assert(err error, v any) {
+	_, _ = err.(*fs.PathError)               // want `type assertion on an error misses wrapped errors; use errors.As`
+	_, _ = err.(interface{ Timeout() bool }) // want `type assertion on an error misses wrapped errors; use errors.As`
+	_ = err.(*codeError)                     // want `type assertion on an error misses wrapped errors; use errors.As`
+
+	switch err.(type) { // want `type switch on an error misses wrapped errors; use errors.As`
+	case *fs.PathError:
+	}
+	switch e := err.(type) { // want `type switch on an error misses wrapped errors; use errors.As`
+	case interface{ Unwrap() error }:
+		_ = e
+	case *codeError:
+	}
+
+	// Assertions to Unwrap interfaces walk the tree: they are what errors.As
+	// itself does.
+	_, _ = err.(interface{ Unwrap() error })
+	_, _ = err.(interface{ Unwrap() []error })
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		_ = u.Unwrap()
+	case interface{ Unwrap() []error }:
+		_ = u.Unwrap()
+	}
+
+	_, _ = v.(error) // ok: v is not an error
+
+	var pathErr *fs.PathError
+	_ = errors.As(err, &pathErr) // ok
+}
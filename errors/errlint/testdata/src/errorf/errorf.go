@@ -0,0 +1,27 @@
+package errorf
+
+import (
+	"errors"
+	"fmt"
+)
+
+type myError struct{}
+
+func (myError) Error() string { return "my error" }
+
+func wrap(err error, e myError, args []any) {
+	_ = fmt.Errorf("a: %v", err)           // want `fmt.Errorf formats an error with %v; use %w to wrap it`
+	_ = fmt.Errorf("a: %s", err)           // want `fmt.Errorf formats an error with %s; use %w to wrap it`
+	_ = fmt.Errorf("%d %+v", 1, e)         // want `fmt.Errorf formats an error with %v; use %w to wrap it`
+	_ = fmt.Errorf("%*d: %v", 3, 4, err)   // want `fmt.Errorf formats an error with %v; use %w to wrap it`
+	_ = fmt.Errorf("100%%: %v", err)       // want `fmt.Errorf formats an error with %v; use %w to wrap it`
+	_ = fmt.Errorf("a: %w", err)           // ok: wrapped
+	_ = fmt.Errorf("%w, %w", err, e)       // ok: wrapped twice
+	_ = fmt.Errorf("a: %q", err)           // ok: %q quotes on purpose
+	_ = fmt.Errorf("a: %v", "text")        // ok: not an error
+	_ = fmt.Errorf("a: %v", nil)           // ok: untyped nil
+	_ = fmt.Errorf("%[1]v", err)           // ok: explicit indexes are not followed
+	_ = fmt.Errorf("%v", args...)          // ok: arguments unknown
+	_ = fmt.Sprintf("a: %v", err)          // ok: not fmt.Errorf
+	_ = errors.New(fmt.Sprint("a: ", err)) // ok: not fmt.Errorf
+}
@@ -0,0 +1,23 @@
+package ignore
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrX = errors.New("x")
+
+func ignored(err error) {
+	_ = err == ErrX //errlint:ignore comparing the exact value on purpose
+
+	//errlint:ignore the directive also covers the next line
+	_, _ = err.(interface{ Timeout() bool })
+
+	// A directive has no space after the slashes, so this one doesn't count.
+	_ = fmt.Errorf("%v", err) // errlint:ignore // want `fmt.Errorf formats an error with %v; use %w to wrap it`
+
+	// A directive only covers its own line and the next one.
+	//errlint:ignore
+
+	_ = err == ErrX // want `comparison with sentinel error ErrX misses wrapped errors; use errors.Is`
+}
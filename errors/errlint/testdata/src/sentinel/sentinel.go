@@ -0,0 +1,40 @@
+package sentinel
+
+import (
+	"errors"
+	"io"
+)
+
+var ErrNotFound = errors.New("not found")
+
+type codeError struct{ code int }
+
+func (e *codeError) Error() string { return "code error" }
+
+// Is may compare target with sentinels directly: it is what errors.Is calls.
+func (e *codeError) Is(target error) bool {
+	return target == ErrNotFound && e.code == 404
+}
+
+// Has is not an Is method, so its comparisons are reported.
+func (e *codeError) Has(target error) bool {
+	return target == ErrNotFound // want `comparison with sentinel error ErrNotFound misses wrapped errors; use errors.Is`
+}
+
+func compare(err error) {
+	_ = err == ErrNotFound // want `comparison with sentinel error ErrNotFound misses wrapped errors; use errors.Is`
+	_ = err != io.EOF      // want `comparison with sentinel error EOF misses wrapped errors; use errors.Is`
+	_ = io.EOF == err      // want `comparison with sentinel error EOF misses wrapped errors; use errors.Is`
+	_ = (err) == (io.EOF)  // want `comparison with sentinel error EOF misses wrapped errors; use errors.Is`
+	_ = err == nil         // ok: nil is not a sentinel
+
+	local := ErrNotFound
+	_ = err == local // ok: not a package-level variable
+
+	_ = errors.Is(err, ErrNotFound) // ok
+
+	check := func(target error) bool {
+		return target == ErrNotFound // want `comparison with sentinel error ErrNotFound misses wrapped errors; use errors.Is`
+	}
+	_ = check
+}
@@ -0,0 +1,168 @@
+// Package errtree walks the tree an error forms through Unwrap() error and
+// Unwrap() []error (the latter is what errors.Join and fmt.Errorf with
+// several %w return) and renders it as an indented tree, as JSON or as a
+// flat list of paths.
+//
+// Paths name a node by the child indexes that lead to it from the root:
+// "$" is the error itself, "$[1]" its second child, "$[1][0]" the first
+// child of that one. An error with a single Unwrap() error has one child.
+package errtree
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Children returns the errors err wraps directly.
+func Children(err error) []error {
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		if child := u.Unwrap(); child != nil {
+			return []error{child}
+		}
+	case interface{ Unwrap() []error }:
+		var children []error
+		for _, child := range u.Unwrap() {
+			if child != nil {
+				children = append(children, child)
+			}
+		}
+		return children
+	}
+	return nil
+}
+
+// Walk calls fn for err and every error under it, parents before children,
+// with the path of each one. If fn returns false, the children of that
+// error are skipped.
+func Walk(err error, fn func(path string, err error) bool) {
+	if err != nil {
+		walk("$", err, fn)
+	}
+}
+
+func walk(path string, err error, fn func(string, error) bool) {
+	if !fn(path, err) {
+		return
+	}
+	for i, child := range Children(err) {
+		walk(path+"["+strconv.Itoa(i)+"]", child, fn)
+	}
+}
+
+// Count returns the number of errors in the tree, err included.
+func Count(err error) int {
+	n := 0
+	Walk(err, func(string, error) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+// Flatten returns the leaves of the tree: the errors that wrap nothing,
+// left to right.
+func Flatten(err error) []error {
+	var leaves []error
+	Walk(err, func(_ string, e error) bool {
+		if len(Children(e)) == 0 {
+			leaves = append(leaves, e)
+		}
+		return true
+	})
+	return leaves
+}
+
+// Filter returns every error in the tree whose dynamic type is T, or that
+// implements T when T is an interface. Unlike errors.As, it doesn't stop at
+// the first match.
+func Filter[T error](err error) []T {
+	var matches []T
+	Walk(err, func(_ string, e error) bool {
+		if t, ok := e.(T); ok { //errlint:ignore Filter checks each node, not its subtree
+			matches = append(matches, t)
+		}
+		return true
+	})
+	return matches
+}
+
+// Node is one error of the tree, as encoded by WriteJSON.
+type Node struct {
+	Message  string  `json:"message"`
+	Type     string  `json:"type"`
+	Children []*Node `json:"children,omitempty"`
+}
+
+// Build converts the tree under err to Nodes. It returns nil for a nil
+// error.
+func Build(err error) *Node {
+	if err == nil {
+		return nil
+	}
+	n := &Node{Message: err.Error(), Type: fmt.Sprintf("%T", err)}
+	for _, child := range Children(err) {
+		n.Children = append(n.Children, Build(child))
+	}
+	return n
+}
+
+// WriteJSON writes the tree under err as indented JSON.
+func WriteJSON(w io.Writer, err error) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(Build(err))
+}
+
+// WriteTree writes the tree under err with one error per line, drawn with
+// box characters:
+//
+//	user 1: invalid email | user 2: not found (*errors.joinError)
+//	├── user 1: invalid email (*fmt.wrapError)
+//	│   └── invalid email (*errors.errorString)
+//	└── user 2: not found (*fmt.wrapError)
+//	    └── not found (*errors.errorString)
+//
+// Multi-line messages, like those of errors.Join, are joined with " | ".
+func WriteTree(w io.Writer, err error) error {
+	if err == nil {
+		return nil
+	}
+	var b strings.Builder
+	writeNode(&b, "", "", err)
+	_, werr := io.WriteString(w, b.String())
+	return werr
+}
+
+func writeNode(b *strings.Builder, prefix, childPrefix string, err error) {
+	fmt.Fprintf(b, "%s%s (%T)\n", prefix, oneLine(err.Error()), err)
+	children := Children(err)
+	for i, child := range children {
+		if i == len(children)-1 {
+			writeNode(b, childPrefix+"└── ", childPrefix+"    ", child)
+		} else {
+			writeNode(b, childPrefix+"├── ", childPrefix+"│   ", child)
+		}
+	}
+}
+
+// WriteList writes every error in the tree as "path: message", one per
+// line.
+func WriteList(w io.Writer, err error) error {
+	var b strings.Builder
+	Walk(err, func(path string, e error) bool {
+		fmt.Fprintf(&b, "%s: %s\n", path, oneLine(e.Error()))
+		return true
+	})
+	_, werr := io.WriteString(w, b.String())
+	return werr
+}
+
+// oneLine keeps multi-line messages, such as those of errors.Join, on a
+// single line.
+func oneLine(msg string) string {
+	return strings.ReplaceAll(msg, "\n", " | ")
+}
@@ -0,0 +1,167 @@
+package errtree_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"reflect"
+	"testing"
+
+	"github.com/salmomascarenhas/go-study-exercises/errors/errtree"
+)
+
+type codeError struct{ code int }
+
+func (e codeError) Error() string { return fmt.Sprintf("code %d", e.code) }
+
+var errEmail = errors.New("invalid email")
+
+// testTree mixes errors.Join and %w four levels deep:
+//
+//	request: %w
+//	└── Join
+//	    ├── user 1: %w
+//	    │   └── Join(errEmail, codeError{404})
+//	    └── user 2: %w
+//	        └── db: %w
+//	            └── codeError{500}
+func testTree() error {
+	return fmt.Errorf("request: %w", errors.Join(
+		fmt.Errorf("user 1: %w", errors.Join(errEmail, codeError{404})),
+		fmt.Errorf("user 2: %w", fmt.Errorf("db: %w", codeError{500})),
+	))
+}
+
+func TestCount(t *testing.T) {
+	if got := errtree.Count(testTree()); got != 9 {
+		t.Errorf("Count = %d, want 9", got)
+	}
+	if got := errtree.Count(nil); got != 0 {
+		t.Errorf("Count(nil) = %d, want 0", got)
+	}
+	if got := errtree.Count(errors.Join(nil, errEmail, nil)); got != 2 {
+		t.Errorf("Count skips nil children: got %d, want 2", got)
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	got := errtree.Flatten(testTree())
+	want := []error{errEmail, codeError{404}, codeError{500}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Flatten = %v, want %v", got, want)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	codes := errtree.Filter[codeError](testTree())
+	if want := []codeError{{404}, {500}}; !reflect.DeepEqual(codes, want) {
+		t.Errorf("Filter[codeError] = %v, want %v", codes, want)
+	}
+	joins := errtree.Filter[interface {
+		error
+		Unwrap() []error
+	}](testTree())
+	if len(joins) != 2 {
+		t.Errorf("Filter found %d joins, want 2", len(joins))
+	}
+	if got := errtree.Filter[*fs.PathError](testTree()); len(got) != 0 {
+		t.Errorf("Filter of an absent type = %v, want none", got)
+	}
+}
+
+func TestWalkStops(t *testing.T) {
+	var paths []string
+	errtree.Walk(testTree(), func(path string, err error) bool {
+		paths = append(paths, path)
+		return path != "$[0][0]"
+	})
+	// Returning false skips $[0][0]'s children, not its siblings.
+	want := []string{"$", "$[0]", "$[0][0]", "$[0][1]", "$[0][1][0]", "$[0][1][0][0]"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("visited %q, want %q", paths, want)
+	}
+}
+
+func TestWriteList(t *testing.T) {
+	var buf bytes.Buffer
+	if err := errtree.WriteList(&buf, testTree()); err != nil {
+		t.Fatal(err)
+	}
+	want := `$: request: user 1: invalid email | code 404 | user 2: db: code 500
+$[0]: user 1: invalid email | code 404 | user 2: db: code 500
+$[0][0]: user 1: invalid email | code 404
+$[0][0][0]: invalid email | code 404
+$[0][0][0][0]: invalid email
+$[0][0][0][1]: code 404
+$[0][1]: user 2: db: code 500
+$[0][1][0]: db: code 500
+$[0][1][0][0]: code 500
+`
+	if got := buf.String(); got != want {
+		t.Errorf("WriteList:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteTree(t *testing.T) {
+	var buf bytes.Buffer
+	if err := errtree.WriteTree(&buf, testTree()); err != nil {
+		t.Fatal(err)
+	}
+	want := `request: user 1: invalid email | code 404 | user 2: db: code 500 (*fmt.wrapError)
+└── user 1: invalid email | code 404 | user 2: db: code 500 (*errors.joinError)
+    ├── user 1: invalid email | code 404 (*fmt.wrapError)
+    │   └── invalid email | code 404 (*errors.joinError)
+    │       ├── invalid email (*errors.errorString)
+    │       └── code 404 (errtree_test.codeError)
+    └── user 2: db: code 500 (*fmt.wrapError)
+        └── db: code 500 (*fmt.wrapError)
+            └── code 500 (errtree_test.codeError)
+`
+	if got := buf.String(); got != want {
+		t.Errorf("WriteTree:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	buf.Reset()
+	if err := errtree.WriteTree(&buf, nil); err != nil || buf.Len() != 0 {
+		t.Errorf("WriteTree(nil) wrote %q, %v; want nothing", buf.String(), err)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	err := errtree.WriteJSON(&buf, fmt.Errorf("user 1: %w", errors.Join(errEmail, fmt.Errorf("db: %w", codeError{500}))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "message": "user 1: invalid email\ndb: code 500",
+  "type": "*fmt.wrapError",
+  "children": [
+    {
+      "message": "invalid email\ndb: code 500",
+      "type": "*errors.joinError",
+      "children": [
+        {
+          "message": "invalid email",
+          "type": "*errors.errorString"
+        },
+        {
+          "message": "db: code 500",
+          "type": "*fmt.wrapError",
+          "children": [
+            {
+              "message": "code 500",
+              "type": "errtree_test.codeError"
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("WriteJSON:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/salmomascarenhas/go-study-exercises/errors/errtree"
+)
+
+// DefaultLocale is used when a locale has no translation for a key.
+const DefaultLocale = "en"
+
+// translations holds the fmt format of each message key per locale. The
+// arguments of a key are the same in every locale.
+var translations = map[string]map[string]string{
+	"en": {
+		"sqrt.negative":  "cannot take the square root of a negative number: %g",
+		"sqrt.nan":       "cannot take the square root of NaN",
+		"not_found":      "not found",
+		"already_exists": "already exists",
+		"user.empty_id":  "user ID is empty",
+	},
+	"pt-BR": {
+		"sqrt.negative":  "raiz quadrada de um número negativo: %g",
+		"sqrt.nan":       "raiz quadrada de NaN",
+		"not_found":      "não encontrado",
+		"already_exists": "já existe",
+		"user.empty_id":  "o ID do usuário está vazio",
+	},
+}
+
+// Localizable is an error whose message can be rendered in any locale. It
+// returns the message key and its arguments; an empty key means the error
+// has no translation and its Error() text is used instead.
+type Localizable interface {
+	MessageKey() (key string, args []any)
+}
+
+// Localizer renders errors in one locale. Errors keep their key and
+// arguments until they reach the program's edge, where Localizer turns
+// them into text.
+type Localizer struct {
+	locale string
+}
+
+// NewLocalizer returns a Localizer for locale, a BCP 47 tag or a POSIX
+// locale such as "pt_BR.UTF-8".
+func NewLocalizer(locale string) Localizer {
+	return Localizer{locale: normalizeLocale(locale)}
+}
+
+// Message renders the first error with a message key in err's tree, or
+// err.Error() if there is none.
+func (l Localizer) Message(err error) string {
+	var msg string
+	errtree.Walk(err, func(_ string, e error) bool {
+		if msg != "" {
+			return false
+		}
+		if loc, ok := e.(Localizable); ok { //errlint:ignore Walk visits each node
+			if key, args := loc.MessageKey(); key != "" {
+				msg = l.Translate(key, args...)
+				return false
+			}
+		}
+		return true
+	})
+	if msg == "" && err != nil {
+		return err.Error()
+	}
+	return msg
+}
+
+// Translate formats key with args in the localizer's locale. Without a
+// translation there it tries the other locales of the same language ("pt"
+// and "pt-BR" for "pt-PT"), then DefaultLocale, and returns the key itself
+// when nobody translates it.
+func (l Localizer) Translate(key string, args ...any) string {
+	for _, locale := range l.fallbacks() {
+		if format, ok := translations[locale][key]; ok {
+			return fmt.Sprintf(format, args...)
+		}
+	}
+	return key
+}
+
+func (l Localizer) fallbacks() []string {
+	lang, _, _ := strings.Cut(l.locale, "-")
+	var related []string
+	for locale := range translations {
+		if locale != l.locale && (locale == lang || strings.HasPrefix(locale, lang+"-")) {
+			related = append(related, locale)
+		}
+	}
+	// "pt" sorts before "pt-BR", so the bare language comes first.
+	sort.Strings(related)
+	chain := append([]string{l.locale}, related...)
+	return append(chain, DefaultLocale)
+}
+
+// normalizeLocale turns a BCP 47 tag or a POSIX locale ("pt_BR.UTF-8")
+// into the form translations is keyed by ("pt-BR").
+func normalizeLocale(tag string) string {
+	if i := strings.IndexAny(tag, ".@"); i >= 0 {
+		tag = tag[:i]
+	}
+	parts := strings.Split(strings.ReplaceAll(tag, "_", "-"), "-")
+	parts[0] = strings.ToLower(parts[0])
+	for i := 1; i < len(parts); i++ {
+		if len(parts[i]) == 2 {
+			parts[i] = strings.ToUpper(parts[i])
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// MissingTranslations lists, as "locale: key", every key that some locale
+// translates and another doesn't. Keys in used, such as the ones the code
+// passes to WithKey, are checked as well, so a key no locale translates is
+// reported for every locale. An empty result means every locale is
+// complete.
+func MissingTranslations(used ...string) []string {
+	keys := make(map[string]bool)
+	for _, messages := range translations {
+		for key := range messages {
+			keys[key] = true
+		}
+	}
+	for _, key := range used {
+		keys[key] = true
+	}
+	var missing []string
+	for locale, messages := range translations {
+		for key := range keys {
+			if _, ok := messages[key]; !ok {
+				missing = append(missing, locale+": "+key)
+			}
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/salmomascarenhas/go-study-exercises/errors/errtree"
+)
+
+// emittedErrors returns one error for every message key the code emits,
+// produced the way callers would get it.
+func emittedErrors(t *testing.T) []error {
+	t.Helper()
+	ctx := context.Background()
+	repo := NewMemoryRepository()
+	_, errNotFound := repo.Get(ctx, "42")
+	errEmptyID := repo.Create(ctx, &User{})
+	if err := repo.Create(ctx, &User{ID: "1"}); err != nil {
+		t.Fatal(err)
+	}
+	errExists := repo.Create(ctx, &User{ID: "1"})
+	_, errNegative := raizQuadrada(-4)
+	_, errNaN := raizQuadrada(math.NaN())
+
+	errs := []error{errNotFound, errEmptyID, errExists, errNegative, errNaN}
+	for i, err := range errs {
+		if err == nil {
+			t.Fatalf("error %d: got nil", i)
+		}
+	}
+	return errs
+}
+
+// messageKey returns the first message key in err's tree, as
+// Localizer.Message would use it.
+func messageKey(err error) (key string, args []any) {
+	errtree.Walk(err, func(_ string, e error) bool {
+		if loc, ok := e.(Localizable); ok && key == "" { //errlint:ignore Walk visits each node
+			key, args = loc.MessageKey()
+		}
+		return key == ""
+	})
+	return key, args
+}
+
+func TestEmittedKeysAreTranslated(t *testing.T) {
+	var used []string
+	for _, err := range emittedErrors(t) {
+		key, args := messageKey(err)
+		if key == "" {
+			t.Errorf("%v carries no message key", err)
+			continue
+		}
+		used = append(used, key)
+		if _, ok := translations[DefaultLocale][key]; !ok {
+			t.Errorf("%s has no %s translation", key, DefaultLocale)
+		}
+		for locale := range translations {
+			if msg := NewLocalizer(locale).Message(err); strings.Contains(msg, "%!") {
+				t.Errorf("%s in %s with %v: %q", key, locale, args, msg)
+			}
+		}
+	}
+	want := []string{"not_found", "user.empty_id", "already_exists", "sqrt.negative", "sqrt.nan"}
+	if !reflect.DeepEqual(used, want) {
+		t.Errorf("emitted keys = %q, want %q", used, want)
+	}
+
+	if missing := MissingTranslations(used...); len(missing) != 0 {
+		t.Errorf("MissingTranslations = %q, want none", missing)
+	}
+}
+
+func TestMissingTranslationsUsedKey(t *testing.T) {
+	got := MissingTranslations("not_found", "user.emtpy_id")
+	want := []string{"en: user.emtpy_id", "pt-BR: user.emtpy_id"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MissingTranslations = %q, want %q", got, want)
+	}
+}
+
+func TestLocalizerMessage(t *testing.T) {
+	errs := emittedErrors(t)
+	want := map[string][]string{
+		"en": {
+			"not found",
+			"user ID is empty",
+			"already exists",
+			"cannot take the square root of a negative number: -4",
+			"cannot take the square root of NaN",
+		},
+		"pt-BR": {
+			"não encontrado",
+			"o ID do usuário está vazio",
+			"já existe",
+			"raiz quadrada de um número negativo: -4",
+			"raiz quadrada de NaN",
+		},
+	}
+	// Each locale is asked for by its own tag, then by others that fall
+	// back to it.
+	locales := map[string]string{
+		"en":          "en",
+		"en-US":       "en",
+		"fr":          "en",
+		"":            "en",
+		"pt-BR":       "pt-BR",
+		"pt-PT":       "pt-BR",
+		"pt":          "pt-BR",
+		"pt-br":       "pt-BR",
+		"pt_BR.UTF-8": "pt-BR",
+	}
+	for tag, locale := range locales {
+		t.Run(tag, func(t *testing.T) {
+			l := NewLocalizer(tag)
+			for i, err := range errs {
+				if got := l.Message(err); got != want[locale][i] {
+					t.Errorf("Message(%v) = %q, want %q", err, got, want[locale][i])
+				}
+			}
+		})
+	}
+}
+
+func TestLocalizerMessageWithoutKey(t *testing.T) {
+	err := errors.New("plain")
+	if got := NewLocalizer("pt-BR").Message(err); got != "plain" {
+		t.Errorf("Message = %q, want the Error() text", got)
+	}
+	if got := NewLocalizer("pt-BR").Translate("no.such.key"); got != "no.such.key" {
+		t.Errorf("Translate = %q, want the key itself", got)
+	}
+}
+
+func TestNormalizeLocale(t *testing.T) {
+	tests := map[string]string{
+		"pt_BR.UTF-8": "pt-BR",
+		"pt-br":       "pt-BR",
+		"PT_br":       "pt-BR",
+		"en":          "en",
+		"EN":          "en",
+		"sr_RS@latin": "sr-RS",
+		"es-419":      "es-419",
+	}
+	for in, want := range tests {
+		if got := normalizeLocale(in); got != want {
+			t.Errorf("normalizeLocale(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
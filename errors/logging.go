@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/salmomascarenhas/go-study-exercises/errors/errtree"
+)
+
+// LogValue logs e as a group with its code, message, metadata and cause.
+// A cause that is itself a slog.LogValuer is logged as a nested group.
+func (e *AppError) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("code", string(e.Code)),
+		slog.String("message", e.Message),
+	}
+	if len(e.Meta) > 0 {
+		keys := make([]string, 0, len(e.Meta))
+		for k := range e.Meta {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		meta := make([]any, 0, len(keys))
+		for _, k := range keys {
+			meta = append(meta, slog.Any(k, e.Meta[k]))
+		}
+		attrs = append(attrs, slog.Group("meta", meta...))
+	}
+	if e.Cause != nil {
+		attrs = append(attrs, slog.Any("cause", e.Cause))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue logs s as a group with its message and input. NaN and ±Inf are
+// logged as strings, since JSON has no number for them.
+func (s SqrtError) LogValue() slog.Value {
+	input := slog.Float64("input", s.Input)
+	if math.IsNaN(s.Input) || math.IsInf(s.Input, 0) {
+		input = slog.String("input", fmt.Sprint(s.Input))
+	}
+	return slog.GroupValue(slog.String("message", s.msg), input)
+}
+
+// ErrorAttr returns err as an "error" attribute: a group with the full
+// message and a "chain" group holding every error in err's tree, keyed by
+// its errtree path ("$", "$[0]", ...). Each layer has its type, its message
+// and, for a slog.LogValuer, the attributes it logs itself.
+func ErrorAttr(err error) slog.Attr {
+	return slog.Any("error", errorChain{err: err})
+}
+
+// LogError logs msg at error level with err as an ErrorAttr, followed by
+// args as in slog.Logger.Log.
+func LogError(ctx context.Context, logger *slog.Logger, msg string, err error, args ...any) {
+	if !logger.Enabled(ctx, slog.LevelError) {
+		return
+	}
+	// Skip runtime.Callers and LogError, so that the source is our caller.
+	var pcs [1]uintptr
+	runtime.Callers(2, pcs[:])
+	r := slog.NewRecord(time.Now(), slog.LevelError, msg, pcs[0])
+	r.AddAttrs(ErrorAttr(err))
+	r.Add(args...)
+	_ = logger.Handler().Handle(ctx, r)
+}
+
+// errorChain is the value of an ErrorAttr. It resolves lazily, so an error
+// that is never logged costs nothing.
+type errorChain struct {
+	err error
+	// dedup makes each layer log only the context it adds; see
+	// DedupHandler.
+	dedup bool
+}
+
+func (c errorChain) LogValue() slog.Value {
+	if c.err == nil {
+		return slog.GroupValue()
+	}
+	var layers []any
+	errtree.Walk(c.err, func(path string, e error) bool {
+		layers = append(layers, slog.Group(path, c.layer(e)...))
+		return true
+	})
+	return slog.GroupValue(
+		slog.String("msg", c.err.Error()),
+		slog.Group("chain", layers...),
+	)
+}
+
+func (c errorChain) layer(e error) []any {
+	attrs := []any{slog.String("type", fmt.Sprintf("%T", e))}
+	if msg := e.Error(); !c.dedup {
+		attrs = append(attrs, slog.String("msg", msg))
+	} else if msg = ownContext(e); msg != "" {
+		attrs = append(attrs, slog.String("msg", msg))
+	}
+	if v, ok := e.(slog.LogValuer); ok { //errlint:ignore one layer of the chain
+		if value := v.LogValue().Resolve(); value.Kind() == slog.KindGroup {
+			for _, a := range value.Group() {
+				// The cause is the next layer of the chain.
+				if a.Key != "cause" {
+					attrs = append(attrs, a)
+				}
+			}
+		}
+	}
+	return attrs
+}
+
+// ownContext returns the part of e's message that its children don't
+// already say: "get user" for fmt.Errorf("get user: %w", err), and "" for
+// an error that only repeats its children, such as an errors.Join.
+func ownContext(e error) string {
+	msg := e.Error()
+	children := errtree.Children(e)
+	if len(children) == 0 {
+		return msg
+	}
+	texts := make([]string, len(children))
+	for i, child := range children {
+		texts[i] = child.Error()
+	}
+	inner := strings.Join(texts, "\n")
+	switch {
+	case msg == inner:
+		return ""
+	case strings.HasSuffix(msg, ": "+inner):
+		return strings.TrimSuffix(msg, ": "+inner)
+	}
+	return msg
+}
+
+// DedupHandler wraps a slog.Handler so that every ErrorAttr it handles
+// logs, for each layer of the chain, only the context that layer adds
+// instead of its full message. Without it, a chain of n wrapping layers
+// repeats the innermost message n times.
+type DedupHandler struct {
+	slog.Handler
+}
+
+func NewDedupHandler(h slog.Handler) *DedupHandler {
+	return &DedupHandler{Handler: h}
+}
+
+func (h *DedupHandler) Handle(ctx context.Context, r slog.Record) error {
+	out := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(dedupAttr(a))
+		return true
+	})
+	return h.Handler.Handle(ctx, out)
+}
+
+func (h *DedupHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	deduped := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		deduped[i] = dedupAttr(a)
+	}
+	return &DedupHandler{Handler: h.Handler.WithAttrs(deduped)}
+}
+
+func (h *DedupHandler) WithGroup(name string) slog.Handler {
+	return &DedupHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+// dedupAttr turns the errorChain values in a, at any depth, into deduped
+// ones.
+func dedupAttr(a slog.Attr) slog.Attr {
+	switch a.Value.Kind() {
+	case slog.KindLogValuer:
+		if c, ok := a.Value.LogValuer().(errorChain); ok {
+			c.dedup = true
+			a.Value = slog.AnyValue(c)
+		}
+	case slog.KindGroup:
+		group := a.Value.Group()
+		attrs := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			attrs[i] = dedupAttr(ga)
+		}
+		a.Value = slog.GroupValue(attrs...)
+	}
+	return a
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// logTestError is an error tree with every kind of layer ErrorAttr handles:
+// fmt.Errorf wrapping, errors.Join, AppError with metadata and SqrtError
+// with a finite and a NaN input.
+func logTestError() error {
+	_, negative := raizQuadrada(-4)
+	_, nan := raizQuadrada(math.NaN())
+	return fmt.Errorf("handler: %w", errors.Join(
+		fmt.Errorf("get user %q: %w", "42", ErrorNotFound),
+		fmt.Errorf("calculando: %w", negative),
+		WrapAppError(nan, CodeInternal, "boom").WithMeta("request", 7),
+	))
+}
+
+func TestLogErrorGolden(t *testing.T) {
+	// Drop the time, so the output is the same on every run.
+	opts := &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}
+	handlers := map[string]func(*bytes.Buffer) slog.Handler{
+		"json":       func(b *bytes.Buffer) slog.Handler { return slog.NewJSONHandler(b, opts) },
+		"json_dedup": func(b *bytes.Buffer) slog.Handler { return NewDedupHandler(slog.NewJSONHandler(b, opts)) },
+		"text":       func(b *bytes.Buffer) slog.Handler { return slog.NewTextHandler(b, opts) },
+		"text_dedup": func(b *bytes.Buffer) slog.Handler { return NewDedupHandler(slog.NewTextHandler(b, opts)) },
+	}
+	for name, newHandler := range handlers {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(newHandler(&buf))
+			LogError(context.Background(), logger, "request failed", logTestError(), "path", "/users/42")
+			// Through With, the attribute goes to the handler's WithAttrs.
+			logger.With(ErrorAttr(fmt.Errorf("lookup: %w", ErrorNotFound))).Info("with")
+			checkGolden(t, filepath.Join("testdata", name+".golden"), buf.Bytes())
+		})
+	}
+}
+
+func checkGolden(t *testing.T, path string, got []byte) {
+	t.Helper()
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s:\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
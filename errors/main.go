@@ -4,44 +4,150 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"net/mail"
+	"strings"
+
+	"github.com/salmomascarenhas/go-study-exercises/errors/stacktrace"
+	"github.com/salmomascarenhas/go-study-exercises/typeparametersgenerics/result"
 )
 
 
 
 
 
+// User is what NewUser builds; UserRepository stores it by ID.
 type User struct {
-	foo string
+	ID    string `json:"id"`
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+	Age   int    `json:"age,omitempty"`
+}
+
+// The options of NewUser wrap these sentinels, so callers tell which field
+// was invalid with errors.Is, even when several were.
+var (
+	ErrInvalidName  = errors.New("invalid name")
+	ErrInvalidEmail = errors.New("invalid email")
+	ErrInvalidAge   = errors.New("invalid age")
+)
+
+// Option sets a field of the User built by NewUser, or returns why it
+// can't.
+type Option func(*User) error
+
+func WithName(name string) Option {
+	return func(u *User) error {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return fmt.Errorf("%w: must not be empty", ErrInvalidName)
+		}
+		u.Name = name
+		return nil
+	}
+}
+
+func WithEmail(email string) Option {
+	return func(u *User) error {
+		// ParseAddress also accepts "Name <addr>"; only a bare address is valid.
+		addr, err := mail.ParseAddress(email)
+		if err != nil || addr.Address != email {
+			return fmt.Errorf("%w: %q is not an email address", ErrInvalidEmail, email)
+		}
+		u.Email = email
+		return nil
+	}
 }
 
-func (u User) Foo() {
-	fmt.Println(u.foo)
+func WithAge(age int) Option {
+	return func(u *User) error {
+		if age < 0 || age > 150 {
+			return fmt.Errorf("%w: %d is not between 0 and 150", ErrInvalidAge, age)
+		}
+		u.Age = age
+		return nil
+	}
 }
 
 // Capturando erros.
-func NewUser(wantError bool) (*User, error) {
-	if wantError {
-		return nil, fmt.Errorf("error")
+// NewUser applies every option, even after one fails, so that the error
+// reports all invalid fields at once: it is an INVALID_ARGUMENT AppError
+// caused by the joined option errors, with a stack trace.
+func NewUser(opts ...Option) (*User, error) {
+	u := &User{}
+	var errs []error
+	for _, opt := range opts {
+		if err := opt(u); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, WrapAppError(stacktrace.Join(errs...), CodeInvalidArgument, "invalid user")
 	}
-	return &User{foo: "bar"}, nil
+	return u, nil
 }
 
+// NewUserResult is NewUser returning a result.Result, to chain with
+// result.Map and result.FlatMap.
+func NewUserResult(opts ...Option) result.Result[*User] {
+	return result.Of(NewUser(opts...))
+}
 
 // Criando os próprios erros.
 
-type SqrtError struct {msg string}
+// ErrNegativeSqrt matches, through errors.Is, any SqrtError caused by a
+// negative input, -Inf included.
+var ErrNegativeSqrt = errors.New("não é possível calcular raiz quadrada de um número negativo")
+
+// SqrtError keeps the input that raizQuadrada refused.
+type SqrtError struct {
+	msg   string
+	Input float64
+}
+
+func (s SqrtError) Error() string { return fmt.Sprintf("%s: %g", s.msg, s.Input) }
+
+// Is reports whether target is ErrNegativeSqrt and s was caused by a
+// negative input. NaN is neither negative nor positive, so it doesn't match.
+func (s SqrtError) Is(target error) bool {
+	return target == ErrNegativeSqrt && s.Input < 0
+}
+
+// Code classifies every SqrtError as INVALID_ARGUMENT for CodeOf,
+// HTTPStatusOf and ExitCodeOf, without an AppError in its tree that
+// errors.Is would match against every other invalid argument.
+func (s SqrtError) Code() Code { return CodeInvalidArgument }
 
-func (s SqrtError) Error() string {return s.msg}
+// MessageKey lets a Localizer render s in any locale; msg is only the
+// Portuguese text of Error().
+func (s SqrtError) MessageKey() (string, []any) {
+	switch {
+	case math.IsNaN(s.Input):
+		return "sqrt.nan", nil
+	case s.Input < 0:
+		return "sqrt.negative", []any{s.Input}
+	}
+	return "", nil
+}
 
+// raizQuadrada returns the square root of x. -0 and +Inf are valid inputs
+// (their roots are -0 and +Inf); negative numbers, -Inf and NaN are not.
 func raizQuadrada(x float64) (float64, error) {
-	if x < 0 {
-		return 0, SqrtError{msg: "raiz quadrada de um número negativo"}
+	switch {
+	case math.IsNaN(x):
+		return 0, SqrtError{msg: "raiz quadrada de NaN", Input: x}
+	case x < 0:
+		return 0, SqrtError{msg: "raiz quadrada de um número negativo", Input: x}
 	}
 	// math lib does not return errors, but return NaN (not a number). Calable sentinel values.
 	resultado := math.Sqrt(x)
 	return resultado, nil
 }
 
+// raizQuadradaResult is raizQuadrada returning a result.Result.
+func raizQuadradaResult(x float64) result.Result[float64] {
+	return result.Of(raizQuadrada(x))
+}
+
 // func main() {
 // 	x := 4.0
 // 	resultado, err := raizQuadrada(x)
@@ -55,7 +161,7 @@ func raizQuadrada(x float64) (float64, error) {
 
 // Comparando tipos de erros (Errors.is e Errors.As).
 
-var ErrorNotFound = errors.New("not found")
+var ErrorNotFound = NewAppError(CodeNotFound, "not found").WithKey("not_found")
 
 // func foo() error { return ErrorNotFound}
 	
@@ -109,7 +215,9 @@ func foo() error { return SqrtError{msg: "foo"} }
 // 	}
 // }
 
-// var ErrNegativeSqrt = errors.New("não é possível calcular raiz quadrada de um número negativo")
+// ErrNegativeSqrt is declared above, next to SqrtError. With SqrtError.Is the
+// errors.Unwrap call isn't needed either: errors.Is(err, ErrNegativeSqrt)
+// walks every wrapping layer by itself.
 
 
 // Explain the errors.Join method
@@ -117,9 +225,10 @@ func foo() error { return SqrtError{msg: "foo"} }
 // The errors.Join function takes a slice of errors and returns a new error that contains all the
 // errors joined together.
 //
-// The errors.Join function creates a new error using fmt.Errorf and joins the errors together
-// using the ": " separator. It also adds a newline character before each error message except
-// for the first one.
+// The errors.Join function returns an error whose message is the message of each error,
+// separated by a newline. Nil errors are skipped, and if every error is nil, Join returns nil.
+// The joined errors stay reachable: the result has an Unwrap() []error method, so errors.Is
+// and errors.As check each of them.
 //
 // Here's an example of using errors.Join:
 // err1 := errors.New("error 1")
@@ -130,11 +239,180 @@ func foo() error { return SqrtError{msg: "foo"} }
 // fmt.Println(err)
 // Output:
 // error 1
-// : error 2
-// : error 3
+// error 2
+// error 3
+//
+// Joins can nest, forming a tree. The errtree package walks that tree, following both
+// Unwrap() error and Unwrap() []error, and prints it:
+// errtree.WriteTree(os.Stdout, errors.Join(err1, fmt.Errorf("wrapped: %w", err2)))
+// Output:
+// error 1 | wrapped: error 2 (*errors.joinError)
+// ├── error 1 (*errors.errorString)
+// └── wrapped: error 2 (*fmt.wrapError)
+//     └── error 2 (*errors.errorString)
 //
 // The errors.Join function can be used to create a new error that contains multiple errors
 // from different sources. It can be useful when you want to collect and report multiple errors
 // at once.
 //
 
+// Erros estruturados (AppError).
+// AppError carries a stable Code, a Message safe to show users, the internal
+// Cause and Meta for anything else worth logging. Since AppError.Is compares
+// codes, errors.Is matches any error with the same code through any number of
+// wrapping layers, and the code decides the HTTP status or exit code.
+//
+// func findUser(id int) error {
+// 	return ErrorNotFound.WithMeta("id", id)
+// }
+//
+// func main() {
+// 	err := fmt.Errorf("loading profile: %w", findUser(42))
+// 	fmt.Println(errors.Is(err, ErrorNotFound)) // true
+// 	fmt.Println(CodeOf(err), HTTPStatusOf(err), ExitCodeOf(err)) // NOT_FOUND 404 66
+// 	fmt.Println(SafeMessage(err)) // not found
+// }
+
+// Matemática segura (pacote safemath).
+// raizQuadrada only guards math.Sqrt. The safemath package does the same for
+// Sqrt, Log, Pow, Asin and Acos, and for integer Add, Mul and Div, returning a
+// *safemath.Error instead of NaN, ±Inf, a panic or a silent wraparound.
+//
+// func main() {
+// 	_, err := safemath.Mul(int64(math.MaxInt64), 2)
+// 	fmt.Println(err) // safemath: Mul(9223372036854775807, 2): result overflows
+// 	fmt.Println(errors.Is(err, safemath.ErrOverflow)) // true
+//
+// 	var mathErr *safemath.Error
+// 	if _, err := safemath.Log(0); errors.As(err, &mathErr) {
+// 		fmt.Println(mathErr.Op, mathErr.Args) // Log [0]
+// 	}
+// }
+
+// Erros com stack trace (pacote stacktrace).
+// An error from fmt.Errorf says what went wrong, not where. stacktrace.New,
+// Errorf, Wrap and Join record the call stack when the error is created:
+// %v prints just the message and %+v the message plus every frame.
+//
+// func main() {
+// 	err := stacktrace.Wrap(stacktrace.New("error"), "creating user")
+// 	fmt.Printf("%v\n", err) // creating user: error
+// 	fmt.Printf("%+v\n", err) // creating user: error, the frames of Wrap,
+// 	// then "caused by: error" and the frames of New
+// }
+
+// Tentando de novo (pacote retry).
+// Some failures go away on their own. retry.Do and retry.DoValue call a
+// function again, waiting longer each time, while its errors are transient
+// (see retry.Classifier) and the Policy budget and the context allow. If they
+// give up, the error joins the error of every attempt. Errors whose code no
+// retry can fix, like those of NewUser, fail on the first attempt.
+// flakyNewUser is in retry_test.go.
+//
+// func main() {
+// 	policy := retry.Policy{MaxAttempts: 5, InitialDelay: 10 * time.Millisecond, Jitter: 0.2}
+// 	user, err := retry.DoValue(context.Background(), policy, flakyNewUser(2, WithName("bar")))
+// 	fmt.Println(user.Name, err) // bar <nil>, on the third attempt
+//
+// 	_, err = retry.DoValue(context.Background(), policy, flakyNewUser(10, WithName("bar")))
+// 	fmt.Println(err)
+// 	// attempt 1: creating user: user service unavailable
+// 	// ...
+// 	// attempt 5: creating user: user service unavailable
+// 	// retry: budget exhausted
+// }
+
+// Repositório de usuários (UserRepository).
+// ErrorNotFound finally has a real producer: the UserRepository backends
+// (MemoryRepository and FileRepository) wrap it when an ID is unknown, and
+// wrap ErrAlreadyExists when Create gets a taken ID.
+//
+// func main() {
+// 	ctx := context.Background()
+// 	repo := NewFileRepository("users.json")
+// 	_, err := repo.Get(ctx, "42")
+// 	fmt.Println(err)                          // get user "42": not found
+// 	fmt.Println(errors.Is(err, ErrorNotFound)) // true
+// }
+
+// Recuperando panics (pacote recovery).
+// A panic is not an error, but recover() can turn it into one. recovery.Recover
+// does that in a deferred call, recovery.SafeGo does it for a goroutine and
+// recovery.Collector for a group of them. If the panic value is an error, the
+// resulting *recovery.PanicError unwraps to it.
+//
+// func main() {
+// 	err := <-recovery.SafeGo(func() error {
+// 		panic(ErrorNotFound)
+// 	})
+// 	fmt.Println(err)                          // panic: not found
+// 	fmt.Println(errors.Is(err, ErrorNotFound)) // true
+// }
+
+// Mensagens traduzidas (Localizer).
+// Error() is for logs, in whatever language the code was written. For users,
+// SqrtError and AppError also carry a message key and its arguments, and a
+// Localizer renders them in pt-BR or en at the edge of the program. A key
+// without a translation falls back to DefaultLocale, and MissingTranslations
+// lists the gaps.
+//
+// func main() {
+// 	_, err := raizQuadrada(-4)
+// 	err = fmt.Errorf("calculando: %w", err)
+// 	fmt.Println(NewLocalizer("pt-BR").Message(err)) // raiz quadrada de um número negativo: -4
+// 	fmt.Println(NewLocalizer("en").Message(err))    // cannot take the square root of a negative number: -4
+// 	fmt.Println(NewLocalizer("fr").Message(ErrorNotFound)) // not found
+// 	fmt.Println(MissingTranslations()) // []
+// }
+
+// Logando erros (log/slog).
+// fmt.Println(err) flattens everything into one string. AppError and SqrtError
+// implement slog.LogValuer, so slog logs their code, message and input as
+// fields, and LogError logs every layer of the error tree. Wrapped in a
+// DedupHandler, each layer only logs the context it adds.
+//
+// func main() {
+// 	logger := slog.New(NewDedupHandler(slog.NewJSONHandler(os.Stdout, nil)))
+// 	_, err := NewMemoryRepository().Get(context.Background(), "42")
+// 	LogError(context.Background(), logger, "request failed", err, "path", "/users/42")
+// 	// {"level":"ERROR","msg":"request failed","error":{"msg":"get user \"42\": not found",
+// 	// "chain":{"$":{"type":"*fmt.wrapError","msg":"get user \"42\""},
+// 	// "$[0]":{"type":"*main.AppError","msg":"not found","code":"NOT_FOUND","message":"not found"}}},
+// 	// "path":"/users/42"}
+// }
+
+// Opções funcionais (NewUser).
+// NewUser takes options instead of positional arguments, and each option
+// validates its own input. Every invalid option is reported, joined in one
+// error, and errors.Is tells which fields failed.
+//
+// func main() {
+// 	_, err := NewUser(WithName(" "), WithEmail("ana"), WithAge(30))
+// 	fmt.Println(err)
+// 	// invalid user: invalid name: must not be empty
+// 	// invalid email: "ana" is not an email address
+// 	fmt.Println(errors.Is(err, ErrInvalidName), errors.Is(err, ErrInvalidAge)) // true false
+// 	fmt.Println(CodeOf(err)) // INVALID_ARGUMENT
+// }
+
+// Result em vez de (valor, erro) (pacote result).
+// result.Result[T] holds a value or an error, so fallible steps chain
+// without an if err != nil after each one: result.Map and result.FlatMap
+// skip the rest of the chain after the first error. result.Of and Get
+// convert from and to (T, error), and option.Option[T] does the same for
+// (T, bool).
+//
+// func main() {
+// 	quarta := result.FlatMap(raizQuadradaResult(16), raizQuadradaResult)
+// 	fmt.Println(quarta) // Ok(2)
+//
+// 	dobro := result.Map(raizQuadradaResult(-16), func(x float64) float64 { return 2 * x })
+// 	fmt.Println(dobro.OrElse(0))                         // 0
+// 	fmt.Println(errors.Is(dobro.Err(), ErrNegativeSqrt)) // true
+//
+// 	nome := result.Map(NewUserResult(WithName("Ana")), func(u *User) string { return u.Name })
+// 	fmt.Println(nome.Unwrap()) // Ana
+//
+// 	email := result.ToOption(result.Map(NewUserResult(WithEmail("ana")), func(u *User) string { return u.Email }))
+// 	fmt.Println(email) // None
+// }
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/salmomascarenhas/go-study-exercises/typeparametersgenerics/result"
+)
+
+func TestRaizQuadrada(t *testing.T) {
+	negZero := math.Copysign(0, -1)
+	tests := []struct {
+		name     string
+		x        float64
+		want     float64
+		wantErr  bool
+		negative bool // errors.Is(err, ErrNegativeSqrt)
+	}{
+		{name: "4", x: 4, want: 2},
+		{name: "-4", x: -4, wantErr: true, negative: true},
+		{name: "NaN", x: math.NaN(), wantErr: true},
+		{name: "+Inf", x: math.Inf(1), want: math.Inf(1)},
+		{name: "-Inf", x: math.Inf(-1), wantErr: true, negative: true},
+		{name: "-0", x: negZero, want: negZero},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := raizQuadrada(tt.x)
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("raizQuadrada(%v): unexpected error %v", tt.x, err)
+				}
+				// == can't tell -0 from 0, so compare the sign too.
+				if got != tt.want || math.Signbit(got) != math.Signbit(tt.want) {
+					t.Errorf("raizQuadrada(%v) = %v, want %v", tt.x, got, tt.want)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("raizQuadrada(%v) = %v, want an error", tt.x, got)
+			}
+			if got != 0 {
+				t.Errorf("raizQuadrada(%v) returned %v with its error, want 0", tt.x, got)
+			}
+
+			wrapped := fmt.Errorf("handler: %w", fmt.Errorf("calculando: %w", err))
+			if is := errors.Is(wrapped, ErrNegativeSqrt); is != tt.negative {
+				t.Errorf("errors.Is(err, ErrNegativeSqrt) = %v, want %v", is, tt.negative)
+			}
+			var sqrtErr SqrtError
+			if !errors.As(wrapped, &sqrtErr) {
+				t.Fatalf("errors.As found no SqrtError in %v", wrapped)
+			}
+			if in := sqrtErr.Input; in != tt.x && !(math.IsNaN(in) && math.IsNaN(tt.x)) {
+				t.Errorf("Input = %v, want %v", in, tt.x)
+			}
+			if CodeOf(wrapped) != CodeInvalidArgument {
+				t.Errorf("CodeOf = %v, want %v", CodeOf(wrapped), CodeInvalidArgument)
+			}
+		})
+	}
+}
+
+func TestRaizQuadradaResult(t *testing.T) {
+	quarta := result.FlatMap(raizQuadradaResult(16), raizQuadradaResult)
+	if got, err := quarta.Get(); got != 2 || err != nil {
+		t.Errorf("fourth root of 16 = %v, %v; want 2, nil", got, err)
+	}
+
+	calls := 0
+	dobro := result.Map(raizQuadradaResult(-16), func(x float64) float64 { calls++; return 2 * x })
+	if calls != 0 {
+		t.Errorf("Map called f %d times after an error, want 0", calls)
+	}
+	if !errors.Is(dobro.Err(), ErrNegativeSqrt) || dobro.OrElse(0) != 0 {
+		t.Errorf("got %v, want ErrNegativeSqrt", dobro)
+	}
+	var sqrtErr SqrtError
+	if !errors.As(dobro.Err(), &sqrtErr) || sqrtErr.Input != -16 {
+		t.Errorf("errors.As found no SqrtError for -16 in %v", dobro.Err())
+	}
+}
+
+func TestNewUserResult(t *testing.T) {
+	nome := result.Map(NewUserResult(WithName("Ana")), func(u *User) string { return u.Name })
+	if got := nome.Unwrap(); got != "Ana" {
+		t.Errorf("name = %q, want Ana", got)
+	}
+
+	calls := 0
+	email := result.Map(NewUserResult(WithEmail("ana"), WithAge(-1)), func(u *User) string { calls++; return u.Email })
+	if calls != 0 {
+		t.Errorf("Map called f %d times after an error, want 0", calls)
+	}
+	if result.ToOption(email).IsSome() {
+		t.Errorf("ToOption(%v) is Some, want None", email)
+	}
+	err := email.Err()
+	if !errors.Is(err, ErrInvalidEmail) || !errors.Is(err, ErrInvalidAge) || errors.Is(err, ErrInvalidName) {
+		t.Errorf("got %v, want ErrInvalidEmail and ErrInvalidAge only", err)
+	}
+	if CodeOf(err) != CodeInvalidArgument {
+		t.Errorf("CodeOf = %v, want %v", CodeOf(err), CodeInvalidArgument)
+	}
+}
+
+func TestNewUserStack(t *testing.T) {
+	_, err := NewUser(WithName(" "), WithAge(200))
+	if err == nil {
+		t.Fatal("NewUser accepted an empty name and an age of 200")
+	}
+	msg := "invalid user: invalid name: must not be empty\ninvalid age: 200 is not between 0 and 150"
+	if got := fmt.Sprintf("%v", err); got != msg {
+		t.Errorf("%%v = %q, want %q", got, msg)
+	}
+
+	// %+v reaches the stack of the join under the AppError.
+	verbose := fmt.Sprintf("%+v", err)
+	if !strings.HasPrefix(verbose, msg+"\n") {
+		t.Errorf("%%+v doesn't start with the message:\n%s", verbose)
+	}
+	for _, frame := range []string{"/errors.NewUser\n", "/errors.TestNewUserStack\n", "main_test.go:"} {
+		if !strings.Contains(verbose, frame) {
+			t.Errorf("%%+v has no %q frame:\n%s", frame, verbose)
+		}
+	}
+
+	if got := fmt.Sprintf("%+v", ErrorNotFound); got != ErrorNotFound.Error() {
+		t.Errorf("%%+v of an AppError without a cause = %q, want its message", got)
+	}
+}
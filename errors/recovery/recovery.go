@@ -0,0 +1,93 @@
+// Package recovery turns panics into errors, so that a panicking function
+// or goroutine fails like any other instead of crashing the program.
+package recovery
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// PanicError is a recovered panic.
+type PanicError struct {
+	// Value is what was passed to panic. For a runtime failure it is a
+	// runtime.Error, and for panic(nil) a *runtime.PanicNilError.
+	Value any
+	// Stack is the stack of the panicking goroutine, as debug.Stack
+	// formats it.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns Value if it is an error, so errors.Is and errors.As look
+// through the panic: a panic(ErrSomething) still matches ErrSomething and a
+// nil map write still matches runtime.Error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// Recover stores a recovered panic as a *PanicError in *errp. It must be
+// deferred directly:
+//
+//	func work() (err error) {
+//		defer recovery.Recover(&err)
+//		...
+//	}
+func Recover(errp *error) {
+	if v := recover(); v != nil {
+		*errp = &PanicError{Value: v, Stack: debug.Stack()}
+	}
+}
+
+// Call runs fn and returns its error, or a *PanicError if it panics.
+func Call(fn func() error) (err error) {
+	defer Recover(&err)
+	return fn()
+}
+
+// SafeGo runs fn in a new goroutine. The returned channel receives fn's
+// error, or a *PanicError if fn panics, and is then closed. It is buffered,
+// so the goroutine never blocks if nobody reads it.
+func SafeGo(fn func() error) <-chan error {
+	ch := make(chan error, 1)
+	go func() {
+		defer close(ch)
+		ch <- Call(fn)
+	}()
+	return ch
+}
+
+// Collector runs functions in goroutines and collects all their errors,
+// panics included. The zero value is ready to use.
+type Collector struct {
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	errs []error
+}
+
+// Go runs fn in a new goroutine.
+func (c *Collector) Go(fn func() error) {
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		if err := Call(fn); err != nil {
+			c.mu.Lock()
+			c.errs = append(c.errs, err)
+			c.mu.Unlock()
+		}
+	}()
+}
+
+// Wait waits for every function started with Go and returns their errors
+// joined with errors.Join, or nil if none failed.
+func (c *Collector) Wait() error {
+	c.wg.Wait()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return errors.Join(c.errs...)
+}
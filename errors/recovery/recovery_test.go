@@ -0,0 +1,162 @@
+package recovery_test
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/salmomascarenhas/go-study-exercises/errors/recovery"
+)
+
+var errBoom = errors.New("boom")
+
+func TestCall(t *testing.T) {
+	if err := recovery.Call(func() error { return nil }); err != nil {
+		t.Errorf("no panic: got %v, want nil", err)
+	}
+	if err := recovery.Call(func() error { return errBoom }); !errors.Is(err, errBoom) {
+		t.Errorf("plain error: got %v, want %v", err, errBoom)
+	}
+}
+
+func TestRecoverValue(t *testing.T) {
+	err := recovery.Call(func() error { panic("kaboom") })
+	var pe *recovery.PanicError
+	if !errors.As(err, &pe) {
+		t.Fatalf("got %T, want *recovery.PanicError", err)
+	}
+	if pe.Value != "kaboom" || err.Error() != "panic: kaboom" {
+		t.Errorf("got Value %v and message %q", pe.Value, err)
+	}
+	if pe.Unwrap() != nil {
+		t.Errorf("a string panic unwraps to %v, want nil", pe.Unwrap())
+	}
+	if !strings.Contains(string(pe.Stack), "TestRecoverValue") {
+		t.Errorf("Stack doesn't show the panicking function:\n%s", pe.Stack)
+	}
+}
+
+func TestRecoverErrorValue(t *testing.T) {
+	err := recovery.Call(func() error { panic(fmt.Errorf("loading config: %w", errBoom)) })
+	if !errors.Is(err, errBoom) {
+		t.Errorf("errors.Is(%v, errBoom) = false, want true", err)
+	}
+	var pe *recovery.PanicError
+	if !errors.As(fmt.Errorf("wrapped: %w", err), &pe) {
+		t.Errorf("errors.As found no PanicError through a wrapping layer")
+	}
+}
+
+func TestRecoverRuntimeError(t *testing.T) {
+	tests := map[string]func() error{
+		"nil map": func() error {
+			var m map[string]int
+			m["x"] = 1
+			return nil
+		},
+		"index out of range": func() error {
+			s := []int{}
+			_ = s[len(s)]
+			return nil
+		},
+		"nil pointer": func() error {
+			var p *struct{ x int }
+			p.x = 1
+			return nil
+		},
+	}
+	for name, fn := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := recovery.Call(fn)
+			var rerr runtime.Error
+			if !errors.As(err, &rerr) {
+				t.Fatalf("got %v, want a runtime.Error", err)
+			}
+		})
+	}
+}
+
+func TestRecoverPanicNil(t *testing.T) {
+	err := recovery.Call(func() error { panic(nil) })
+	var pnil *runtime.PanicNilError
+	if !errors.As(err, &pnil) {
+		t.Errorf("got %v, want a *runtime.PanicNilError", err)
+	}
+}
+
+func TestRecoverNested(t *testing.T) {
+	// The inner Call recovers the inner panic; the outer one sees only the
+	// panic raised while handling it.
+	var inner error
+	outer := recovery.Call(func() error {
+		inner = recovery.Call(func() error { panic(errBoom) })
+		panic(fmt.Errorf("handling: %w", inner))
+	})
+	if !errors.Is(inner, errBoom) {
+		t.Errorf("inner: got %v, want errBoom", inner)
+	}
+	var pe *recovery.PanicError
+	if !errors.As(outer, &pe) || !errors.Is(outer, errBoom) {
+		t.Fatalf("outer: got %v, want a PanicError matching errBoom", outer)
+	}
+	if want := "panic: handling: panic: boom"; outer.Error() != want {
+		t.Errorf("outer = %q, want %q", outer, want)
+	}
+
+	// A panic in a deferred function replaces the one being unwound.
+	err := recovery.Call(func() error {
+		defer func() { panic("second") }()
+		panic("first")
+	})
+	if !errors.As(err, &pe) || pe.Value != "second" {
+		t.Errorf("got %v, want the second panic", err)
+	}
+}
+
+func TestSafeGo(t *testing.T) {
+	err := <-recovery.SafeGo(func() error { panic(errBoom) })
+	if !errors.Is(err, errBoom) {
+		t.Errorf("got %v, want errBoom", err)
+	}
+	ch := recovery.SafeGo(func() error { return nil })
+	if err := <-ch; err != nil {
+		t.Errorf("got %v, want nil", err)
+	}
+	if _, open := <-ch; open {
+		t.Error("channel still open after the result")
+	}
+}
+
+func TestCollector(t *testing.T) {
+	var c recovery.Collector
+	errSlow := errors.New("slow")
+	c.Go(func() error { return nil })
+	c.Go(func() error { panic(errBoom) })
+	c.Go(func() error { return errSlow })
+	c.Go(func() error {
+		var m map[int]int
+		m[0] = 0
+		return nil
+	})
+	err := c.Wait()
+
+	if !errors.Is(err, errBoom) || !errors.Is(err, errSlow) {
+		t.Errorf("got %v, want errBoom and errSlow joined", err)
+	}
+	var rerr runtime.Error
+	if !errors.As(err, &rerr) {
+		t.Errorf("got %v, want the nil map write as a runtime.Error", err)
+	}
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok || len(joined.Unwrap()) != 3 {
+		t.Errorf("got %v, want 3 joined errors", err)
+	}
+
+	var empty recovery.Collector
+	empty.Go(func() error { return nil })
+	if err := empty.Wait(); err != nil {
+		t.Errorf("no failures: got %v, want nil", err)
+	}
+}
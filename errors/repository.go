@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// ErrAlreadyExists is returned by Create for an ID that is taken.
+var ErrAlreadyExists = NewAppError(CodeAlreadyExists, "already exists").WithKey("already_exists")
+
+// UserRepository stores users by ID. Get, Update and Delete wrap
+// ErrorNotFound for an unknown ID, and Create wraps ErrAlreadyExists for a
+// taken one, so callers check them with errors.Is.
+type UserRepository interface {
+	Get(ctx context.Context, id string) (*User, error)
+	Create(ctx context.Context, u *User) error
+	Update(ctx context.Context, u *User) error
+	Delete(ctx context.Context, id string) error
+	// List returns every user, sorted by ID.
+	List(ctx context.Context) ([]*User, error)
+}
+
+var (
+	_ UserRepository = (*MemoryRepository)(nil)
+	_ UserRepository = (*FileRepository)(nil)
+)
+
+// MemoryRepository keeps users in a map. It stores copies, so changing a
+// User after Create or Get doesn't change the repository.
+type MemoryRepository struct {
+	mu    sync.RWMutex
+	users map[string]User
+}
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{users: make(map[string]User)}
+}
+
+func (r *MemoryRepository) Get(ctx context.Context, id string) (*User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	u, ok := r.users[id]
+	if !ok {
+		return nil, fmt.Errorf("get user %q: %w", id, ErrorNotFound)
+	}
+	return &u, nil
+}
+
+func (r *MemoryRepository) Create(ctx context.Context, u *User) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if u.ID == "" {
+		return NewAppError(CodeInvalidArgument, "user ID is empty").WithKey("user.empty_id")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.users[u.ID]; ok {
+		return fmt.Errorf("create user %q: %w", u.ID, ErrAlreadyExists)
+	}
+	r.users[u.ID] = *u
+	return nil
+}
+
+func (r *MemoryRepository) Update(ctx context.Context, u *User) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.users[u.ID]; !ok {
+		return fmt.Errorf("update user %q: %w", u.ID, ErrorNotFound)
+	}
+	r.users[u.ID] = *u
+	return nil
+}
+
+func (r *MemoryRepository) Delete(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.users[id]; !ok {
+		return fmt.Errorf("delete user %q: %w", id, ErrorNotFound)
+	}
+	delete(r.users, id)
+	return nil
+}
+
+func (r *MemoryRepository) List(ctx context.Context) ([]*User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	users := make([]*User, 0, len(r.users))
+	for _, u := range r.users {
+		users = append(users, &u)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return users, nil
+}
+
+// FileRepository keeps users in a JSON file. Every call reads the file,
+// and every change rewrites it, so separate FileRepository values on the
+// same path see each other's changes. A missing file is an empty
+// repository.
+type FileRepository struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileRepository(path string) *FileRepository {
+	return &FileRepository{path: path}
+}
+
+func (r *FileRepository) Get(ctx context.Context, id string) (u *User, err error) {
+	err = r.view(func(m *MemoryRepository) error {
+		u, err = m.Get(ctx, id)
+		return err
+	})
+	return u, err
+}
+
+func (r *FileRepository) Create(ctx context.Context, u *User) error {
+	return r.update(func(m *MemoryRepository) error { return m.Create(ctx, u) })
+}
+
+func (r *FileRepository) Update(ctx context.Context, u *User) error {
+	return r.update(func(m *MemoryRepository) error { return m.Update(ctx, u) })
+}
+
+func (r *FileRepository) Delete(ctx context.Context, id string) error {
+	return r.update(func(m *MemoryRepository) error { return m.Delete(ctx, id) })
+}
+
+func (r *FileRepository) List(ctx context.Context) (users []*User, err error) {
+	err = r.view(func(m *MemoryRepository) error {
+		users, err = m.List(ctx)
+		return err
+	})
+	return users, err
+}
+
+// view loads the file into a MemoryRepository and runs fn on it.
+func (r *FileRepository) view(fn func(*MemoryRepository) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m, err := r.load()
+	if err != nil {
+		return err
+	}
+	return fn(m)
+}
+
+// update is view plus writing the repository back if fn succeeds.
+func (r *FileRepository) update(fn func(*MemoryRepository) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m, err := r.load()
+	if err != nil {
+		return err
+	}
+	if err := fn(m); err != nil {
+		return err
+	}
+	return r.save(m)
+}
+
+func (r *FileRepository) load() (*MemoryRepository, error) {
+	m := NewMemoryRepository()
+	data, err := os.ReadFile(r.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var users []User
+	if err := json.Unmarshal(data, &users); err != nil {
+		return nil, fmt.Errorf("%s: %w", r.path, err)
+	}
+	for _, u := range users {
+		m.users[u.ID] = u
+	}
+	return m, nil
+}
+
+// save writes a temporary file and renames it over the old one, so a
+// failed save never leaves a truncated file behind.
+func (r *FileRepository) save(m *MemoryRepository) error {
+	users, _ := m.List(context.Background())
+	data, err := json.MarshalIndent(users, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(r.path), filepath.Base(r.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), r.path)
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMemoryRepository(t *testing.T) {
+	testRepository(t, func(t *testing.T) UserRepository {
+		return NewMemoryRepository()
+	})
+}
+
+func TestFileRepository(t *testing.T) {
+	testRepository(t, func(t *testing.T) UserRepository {
+		return NewFileRepository(filepath.Join(t.TempDir(), "u.json"))
+	})
+}
+
+// testRepository is the conformance suite every UserRepository backend must
+// pass. newRepo returns an empty repository.
+func testRepository(t *testing.T, newRepo func(t *testing.T) UserRepository) {
+	ctx := context.Background()
+
+	t.Run("missing ID", func(t *testing.T) {
+		repo := newRepo(t)
+		if _, err := repo.Get(ctx, "nobody"); !errors.Is(err, ErrorNotFound) {
+			t.Errorf("Get: got %v, want ErrorNotFound", err)
+		}
+		if err := repo.Update(ctx, &User{ID: "nobody"}); !errors.Is(err, ErrorNotFound) {
+			t.Errorf("Update: got %v, want ErrorNotFound", err)
+		}
+		if err := repo.Delete(ctx, "nobody"); !errors.Is(err, ErrorNotFound) {
+			t.Errorf("Delete: got %v, want ErrorNotFound", err)
+		}
+	})
+
+	t.Run("duplicate Create", func(t *testing.T) {
+		repo := newRepo(t)
+		if err := repo.Create(ctx, &User{ID: "1", Name: "Ana"}); err != nil {
+			t.Fatal(err)
+		}
+		err := repo.Create(ctx, &User{ID: "1", Name: "Bia"})
+		if !errors.Is(err, ErrAlreadyExists) {
+			t.Fatalf("got %v, want ErrAlreadyExists", err)
+		}
+		if u, _ := repo.Get(ctx, "1"); u == nil || u.Name != "Ana" {
+			t.Errorf("failed Create replaced the user: got %+v", u)
+		}
+	})
+
+	t.Run("empty ID", func(t *testing.T) {
+		repo := newRepo(t)
+		if err := repo.Create(ctx, &User{}); CodeOf(err) != CodeInvalidArgument {
+			t.Errorf("got %v, want an INVALID_ARGUMENT error", err)
+		}
+	})
+
+	t.Run("CRUD", func(t *testing.T) {
+		repo := newRepo(t)
+		want := &User{ID: "1", Name: "Ana", Email: "ana@example.com", Age: 30}
+		if err := repo.Create(ctx, want); err != nil {
+			t.Fatal(err)
+		}
+		got, err := repo.Get(ctx, "1")
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Fatalf("Get: got %+v, %v; want %+v", got, err, want)
+		}
+		want.Age = 31
+		if err := repo.Update(ctx, want); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := repo.Get(ctx, "1"); got == nil || got.Age != 31 {
+			t.Errorf("Get after Update: got %+v, want Age 31", got)
+		}
+		if err := repo.Delete(ctx, "1"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repo.Get(ctx, "1"); !errors.Is(err, ErrorNotFound) {
+			t.Errorf("Get after Delete: got %v, want ErrorNotFound", err)
+		}
+	})
+
+	t.Run("List is sorted by ID", func(t *testing.T) {
+		repo := newRepo(t)
+		for _, id := range []string{"c", "a", "d", "b"} {
+			if err := repo.Create(ctx, &User{ID: id}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		users, err := repo.List(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, u := range users {
+			ids = append(ids, u.ID)
+		}
+		if want := []string{"a", "b", "c", "d"}; !reflect.DeepEqual(ids, want) {
+			t.Errorf("got %v, want %v", ids, want)
+		}
+	})
+
+	t.Run("copies are isolated", func(t *testing.T) {
+		repo := newRepo(t)
+		u := &User{ID: "1", Name: "Ana"}
+		if err := repo.Create(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+		u.Name = "changed after Create"
+		got, _ := repo.Get(ctx, "1")
+		if got.Name != "Ana" {
+			t.Errorf("changing the created User changed the stored one: %q", got.Name)
+		}
+		got.Name = "changed after Get"
+		users, _ := repo.List(ctx)
+		users[0].Name = "changed after List"
+		if again, _ := repo.Get(ctx, "1"); again.Name != "Ana" {
+			t.Errorf("changing a returned User changed the stored one: %q", again.Name)
+		}
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		if _, err := repo.Get(ctx, "1"); !errors.Is(err, context.Canceled) {
+			t.Errorf("got %v, want context.Canceled", err)
+		}
+	})
+}
+
+func TestFileRepositoryRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "u.json")
+	want := []*User{
+		{ID: "1", Name: "Ana", Email: "ana@example.com", Age: 30},
+		{ID: "2", Name: "Bia"},
+	}
+	writer := NewFileRepository(path)
+	for _, u := range want {
+		if err := writer.Create(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := NewFileRepository(path).List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("a new FileRepository on the same file got %+v, want %+v", got, want)
+	}
+}
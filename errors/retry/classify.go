@@ -0,0 +1,106 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Class says whether an error is worth retrying.
+type Class int
+
+const (
+	Transient Class = iota
+	Permanent
+)
+
+func (c Class) String() string {
+	if c == Permanent {
+		return "permanent"
+	}
+	return "transient"
+}
+
+// Classifier sorts errors into transient and permanent ones. It checks, in
+// order:
+//
+//  1. errors marked with MarkPermanent: permanent;
+//  2. the rules added with Register and RegisterFunc, in order;
+//  3. context.Canceled and context.DeadlineExceeded: permanent, although
+//     DeadlineExceeded claims to be Temporary;
+//  4. errors with a Temporary() bool method, found with errors.As;
+//
+// and treats anything else as transient.
+type Classifier struct {
+	mu    sync.RWMutex
+	rules []rule
+}
+
+type rule struct {
+	match func(error) bool
+	class Class
+}
+
+// DefaultClassifier is used by policies that don't set their own.
+var DefaultClassifier = &Classifier{}
+
+// Register classifies every error matching target, as errors.Is sees it.
+// Rules are checked in the order they were registered.
+func (c *Classifier) Register(target error, class Class) {
+	c.RegisterFunc(func(err error) bool { return errors.Is(err, target) }, class)
+}
+
+// RegisterFunc classifies every error for which match reports true, for
+// errors that no single target stands for, such as all errors with a given
+// code.
+func (c *Classifier) RegisterFunc(match func(err error) bool, class Class) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rules = append(c.rules, rule{match: match, class: class})
+}
+
+// Classify returns the class of err.
+func (c *Classifier) Classify(err error) Class {
+	var p *permanentError
+	if errors.As(err, &p) {
+		return Permanent
+	}
+
+	c.mu.RLock()
+	rules := c.rules
+	c.mu.RUnlock()
+	for _, r := range rules {
+		if r.match(err) {
+			return r.class
+		}
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return Permanent
+	}
+	var temp interface{ Temporary() bool }
+	if errors.As(err, &temp) {
+		if temp.Temporary() {
+			return Transient
+		}
+		return Permanent
+	}
+	return Transient
+}
+
+// MarkPermanent wraps err so that no Classifier retries it. It returns nil
+// if err is nil.
+func MarkPermanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+
+func (e *permanentError) Unwrap() error { return e.err }
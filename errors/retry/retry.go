@@ -0,0 +1,115 @@
+// Package retry calls a function until it succeeds, waiting longer between
+// each attempt (exponential backoff with jitter), for as long as its errors
+// are transient and the Policy's budget and the context allow.
+//
+// When it gives up, the returned error joins the error of every attempt,
+// each one wrapped as "attempt N: ...", followed by ErrExhausted or the
+// context error if that is why it stopped. errors.Is and errors.As see all
+// of them.
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// ErrExhausted ends the joined error when the Policy's attempt or time
+// budget runs out.
+var ErrExhausted = errors.New("retry: budget exhausted")
+
+// Policy configures Do. The zero value retries forever with a delay growing
+// from 100ms to 10s, so set MaxAttempts, MaxElapsed or a context deadline.
+type Policy struct {
+	// MaxAttempts stops after that many calls; 0 means no limit.
+	MaxAttempts int
+	// MaxElapsed stops when the next wait would end later than that long
+	// after the first call; 0 means no limit.
+	MaxElapsed time.Duration
+
+	// InitialDelay is the wait after the first failure. Each later wait is
+	// Multiplier times longer, up to MaxDelay.
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	Multiplier   float64
+	// Jitter randomizes each wait by up to ±Jitter of its length, e.g. 0.2
+	// for ±20%, so that clients don't retry in lockstep.
+	Jitter float64
+
+	// Classifier decides which errors are worth retrying. Nil means
+	// DefaultClassifier.
+	Classifier *Classifier
+}
+
+func (p Policy) withDefaults() Policy {
+	if p.InitialDelay <= 0 {
+		p.InitialDelay = 100 * time.Millisecond
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = 10 * time.Second
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = 2
+	}
+	if p.Classifier == nil {
+		p.Classifier = DefaultClassifier
+	}
+	return p
+}
+
+// Delay returns the wait after the given failed attempt, counted from 1.
+func (p Policy) Delay(attempt int) time.Duration {
+	p = p.withDefaults()
+	d := float64(p.InitialDelay)
+	for i := 1; i < attempt && d < float64(p.MaxDelay); i++ {
+		d *= p.Multiplier
+	}
+	d = min(d, float64(p.MaxDelay))
+	if p.Jitter > 0 {
+		d += d * p.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(d)
+}
+
+// Do calls fn until it returns nil, a permanent error, the budget of p runs
+// out or ctx is done. See the package documentation for the error returned.
+func Do(ctx context.Context, p Policy, fn func(ctx context.Context) error) error {
+	_, err := DoValue(ctx, p, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
+}
+
+// DoValue is Do for functions that also return a value.
+func DoValue[T any](ctx context.Context, p Policy, fn func(ctx context.Context) (T, error)) (T, error) {
+	p = p.withDefaults()
+	start := time.Now()
+	var errs []error
+	for attempt := 1; ; attempt++ {
+		v, err := fn(ctx)
+		if err == nil {
+			return v, nil
+		}
+		errs = append(errs, fmt.Errorf("attempt %d: %w", attempt, err))
+
+		var zero T
+		if p.Classifier.Classify(err) == Permanent {
+			return zero, errors.Join(errs...)
+		}
+		delay := p.Delay(attempt)
+		if p.MaxAttempts > 0 && attempt >= p.MaxAttempts ||
+			p.MaxElapsed > 0 && time.Since(start)+delay > p.MaxElapsed {
+			return zero, errors.Join(append(errs, ErrExhausted)...)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return zero, errors.Join(append(errs, ctx.Err())...)
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/salmomascarenhas/go-study-exercises/errors/retry"
+)
+
+// fast keeps the waits of a test in the millisecond range.
+var fast = retry.Policy{InitialDelay: time.Millisecond, MaxDelay: 4 * time.Millisecond}
+
+func TestDelay(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name    string
+		policy  retry.Policy
+		attempt int
+		want    time.Duration
+	}{
+		{"defaults, first", retry.Policy{}, 1, 100 * ms},
+		{"defaults, doubles", retry.Policy{}, 3, 400 * ms},
+		{"defaults, capped at 10s", retry.Policy{}, 20, 10 * time.Second},
+		{"defaults, huge attempt", retry.Policy{}, 1 << 30, 10 * time.Second},
+		{"custom, first", retry.Policy{InitialDelay: 10 * ms, Multiplier: 3, MaxDelay: 100 * ms}, 1, 10 * ms},
+		{"custom, grows", retry.Policy{InitialDelay: 10 * ms, Multiplier: 3, MaxDelay: 100 * ms}, 3, 90 * ms},
+		{"custom, capped", retry.Policy{InitialDelay: 10 * ms, Multiplier: 3, MaxDelay: 100 * ms}, 4, 100 * ms},
+		{"multiplier below 1 means 2", retry.Policy{InitialDelay: 10 * ms, Multiplier: 0.5}, 2, 20 * ms},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Delay(tt.attempt); got != tt.want {
+				t.Errorf("Delay(%d) = %v, want %v", tt.attempt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDelayJitter(t *testing.T) {
+	p := retry.Policy{InitialDelay: 100 * time.Millisecond, Jitter: 0.2}
+	for range 100 {
+		if d := p.Delay(1); d < 80*time.Millisecond || d > 120*time.Millisecond {
+			t.Fatalf("Delay(1) = %v, want within ±20%% of 100ms", d)
+		}
+	}
+}
+
+var errFlaky = errors.New("flaky")
+
+// failing returns a function that fails its first n calls with errFlaky
+// and counts every call.
+func failing(n int, calls *int) func(context.Context) (int, error) {
+	return func(context.Context) (int, error) {
+		*calls++
+		if *calls <= n {
+			return 0, fmt.Errorf("call %d: %w", *calls, errFlaky)
+		}
+		return *calls, nil
+	}
+}
+
+func TestDoValueSucceeds(t *testing.T) {
+	var calls int
+	p := fast
+	p.MaxAttempts = 5
+	v, err := retry.DoValue(context.Background(), p, failing(2, &calls))
+	if v != 3 || err != nil || calls != 3 {
+		t.Errorf("got %v, %v after %d calls; want 3, nil after 3", v, err, calls)
+	}
+}
+
+func TestMaxAttempts(t *testing.T) {
+	var calls int
+	p := fast
+	p.MaxAttempts = 3
+	_, err := retry.DoValue(context.Background(), p, failing(10, &calls))
+	if calls != 3 {
+		t.Errorf("made %d calls, want 3", calls)
+	}
+	if !errors.Is(err, retry.ErrExhausted) || !errors.Is(err, errFlaky) {
+		t.Errorf("got %v, want ErrExhausted and errFlaky", err)
+	}
+
+	// The joined error keeps every attempt, in order, then ErrExhausted.
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("got %T, want an errors.Join", err)
+	}
+	errs := joined.Unwrap()
+	if len(errs) != 4 || !errors.Is(errs[3], retry.ErrExhausted) {
+		t.Fatalf("got %d errors, want 3 attempts and ErrExhausted: %v", len(errs), err)
+	}
+	for i, e := range errs[:3] {
+		want := fmt.Sprintf("attempt %d: call %d: flaky", i+1, i+1)
+		if e.Error() != want || !errors.Is(e, errFlaky) {
+			t.Errorf("error %d = %q, want %q wrapping errFlaky", i, e, want)
+		}
+	}
+}
+
+func TestMaxElapsed(t *testing.T) {
+	var calls int
+	p := retry.Policy{InitialDelay: 10 * time.Millisecond, MaxElapsed: 25 * time.Millisecond}
+	start := time.Now()
+	_, err := retry.DoValue(context.Background(), p, failing(100, &calls))
+	if !errors.Is(err, retry.ErrExhausted) {
+		t.Fatalf("got %v, want ErrExhausted", err)
+	}
+	// Waits of 10ms then 20ms: the second would end past 25ms, so it's
+	// never started.
+	if calls != 2 {
+		t.Errorf("made %d calls, want 2", calls)
+	}
+	if elapsed := time.Since(start); elapsed > 25*time.Millisecond {
+		t.Errorf("took %v, want at most MaxElapsed", elapsed)
+	}
+}
+
+func TestContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls int
+	p := retry.Policy{InitialDelay: time.Hour} // only ctx can end the wait
+	time.AfterFunc(10*time.Millisecond, cancel)
+	_, err := retry.DoValue(ctx, p, failing(100, &calls))
+	if !errors.Is(err, context.Canceled) || !errors.Is(err, errFlaky) {
+		t.Errorf("got %v, want context.Canceled and errFlaky", err)
+	}
+	if errors.Is(err, retry.ErrExhausted) {
+		t.Errorf("got %v, the budget wasn't exhausted", err)
+	}
+	if calls != 1 {
+		t.Errorf("made %d calls, want 1", calls)
+	}
+}
+
+func TestPermanentStopsAtOnce(t *testing.T) {
+	calls := 0
+	err := retry.Do(context.Background(), retry.Policy{}, func(context.Context) error {
+		calls++
+		return retry.MarkPermanent(errFlaky)
+	})
+	if calls != 1 || !errors.Is(err, errFlaky) || errors.Is(err, retry.ErrExhausted) {
+		t.Errorf("got %v after %d calls, want errFlaky after 1", err, calls)
+	}
+	if want := "attempt 1: flaky"; err.Error() != want {
+		t.Errorf("error = %q, want %q", err, want)
+	}
+}
+
+type tempError bool
+
+func (e tempError) Error() string   { return fmt.Sprintf("temporary=%v", bool(e)) }
+func (e tempError) Temporary() bool { return bool(e) }
+
+func TestClassify(t *testing.T) {
+	errRegisteredPermanent := errors.New("registered permanent")
+	errRegisteredTransient := errors.New("registered transient")
+	c := &retry.Classifier{}
+	c.Register(errRegisteredPermanent, retry.Permanent)
+	c.Register(errRegisteredTransient, retry.Transient)
+	c.Register(errRegisteredTransient, retry.Permanent) // shadowed by the rule above
+	c.RegisterFunc(func(err error) bool { return strings.HasPrefix(err.Error(), "fatal:") }, retry.Permanent)
+	c.Register(tempError(true), retry.Permanent) // rules come before Temporary()
+
+	tests := []struct {
+		name string
+		err  error
+		want retry.Class
+	}{
+		{"plain", errFlaky, retry.Transient},
+		{"MarkPermanent", retry.MarkPermanent(errFlaky), retry.Permanent},
+		{"MarkPermanent wrapped", fmt.Errorf("x: %w", retry.MarkPermanent(errRegisteredTransient)), retry.Permanent},
+		{"registered permanent", fmt.Errorf("x: %w", errRegisteredPermanent), retry.Permanent},
+		{"first rule wins", errRegisteredTransient, retry.Transient},
+		{"RegisterFunc", errors.New("fatal: disk gone"), retry.Permanent},
+		{"registered over Temporary", tempError(true), retry.Permanent},
+		{"Temporary false", fmt.Errorf("x: %w", tempError(false)), retry.Permanent},
+		{"context canceled", context.Canceled, retry.Permanent},
+		{"deadline exceeded", fmt.Errorf("x: %w", context.DeadlineExceeded), retry.Permanent},
+		{"joined with a permanent one", errors.Join(errFlaky, errRegisteredPermanent), retry.Permanent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.Classify(tt.err); got != tt.want {
+				t.Errorf("Classify(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+
+	// Temporary() true counts as transient when no rule matches.
+	if got := (&retry.Classifier{}).Classify(tempError(true)); got != retry.Transient {
+		t.Errorf("Classify(Temporary() true) = %v, want transient", got)
+	}
+}
+
+func TestPolicyClassifier(t *testing.T) {
+	c := &retry.Classifier{}
+	c.Register(errFlaky, retry.Permanent)
+	var calls int
+	p := fast
+	p.MaxAttempts, p.Classifier = 5, c
+	if _, err := retry.DoValue(context.Background(), p, failing(10, &calls)); !errors.Is(err, errFlaky) || calls != 1 {
+		t.Errorf("got %v after %d calls, want errFlaky after 1", err, calls)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/salmomascarenhas/go-study-exercises/errors/retry"
+	"github.com/salmomascarenhas/go-study-exercises/errors/stacktrace"
+)
+
+var errUnavailable = NewAppError(CodeUnavailable, "user service unavailable")
+
+// flakyNewUser returns a NewUser that fails its first failures calls, like a
+// service that is still starting up.
+func flakyNewUser(failures int, opts ...Option) func(context.Context) (*User, error) {
+	calls := 0
+	return func(context.Context) (*User, error) {
+		calls++
+		if calls <= failures {
+			return nil, stacktrace.Wrap(errUnavailable, "creating user")
+		}
+		return NewUser(opts...)
+	}
+}
+
+var testPolicy = retry.Policy{MaxAttempts: 5, InitialDelay: time.Millisecond, Jitter: 0.2}
+
+func TestRetryFlakyNewUser(t *testing.T) {
+	user, err := retry.DoValue(context.Background(), testPolicy, flakyNewUser(2, WithName("bar")))
+	if err != nil || user.Name != "bar" {
+		t.Errorf("got %+v, %v; want bar on the third attempt", user, err)
+	}
+
+	_, err = retry.DoValue(context.Background(), testPolicy, flakyNewUser(10, WithName("bar")))
+	if !errors.Is(err, retry.ErrExhausted) || !errors.Is(err, errUnavailable) {
+		t.Errorf("got %v, want ErrExhausted and the unavailable error", err)
+	}
+	if CodeOf(err) != CodeUnavailable {
+		t.Errorf("CodeOf = %v, want %v", CodeOf(err), CodeUnavailable)
+	}
+}
+
+func TestRetryStopsOnInvalidArgument(t *testing.T) {
+	// The service recovers, but the user it is asked for is invalid: the
+	// validation error is not retried, even without an attempt limit.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	calls := 0
+	flaky := flakyNewUser(1, WithAge(-1))
+	_, err := retry.DoValue(ctx, retry.Policy{InitialDelay: time.Millisecond}, func(ctx context.Context) (*User, error) {
+		calls++
+		return flaky(ctx)
+	})
+	if calls != 2 {
+		t.Errorf("made %d calls, want 2: one unavailable, one invalid", calls)
+	}
+	if !errors.Is(err, ErrInvalidAge) || errors.Is(err, retry.ErrExhausted) || errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want ErrInvalidAge without exhausting the budget", err)
+	}
+}
+
+func TestRetryClassifiesCodes(t *testing.T) {
+	tests := map[Code]retry.Class{
+		CodeInvalidArgument: retry.Permanent,
+		CodeNotFound:        retry.Permanent,
+		CodeAlreadyExists:   retry.Permanent,
+		CodeUnavailable:     retry.Transient,
+		CodeInternal:        retry.Transient,
+	}
+	for code, want := range tests {
+		err := stacktrace.Wrap(NewAppError(code, "x"), "wrapped")
+		if got := retry.DefaultClassifier.Classify(err); got != want {
+			t.Errorf("Classify(%s) = %v, want %v", code, got, want)
+		}
+	}
+	if got := retry.DefaultClassifier.Classify(errors.New("plain")); got != retry.Transient {
+		t.Errorf("Classify(plain error) = %v, want transient", got)
+	}
+}
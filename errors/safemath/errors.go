@@ -0,0 +1,48 @@
+// Package safemath wraps functions of the math package, and plain integer
+// arithmetic, so that they return an error where they would otherwise
+// produce NaN, an infinity, a panic or a silent wraparound.
+//
+// Every error is an *Error whose Err is one of the sentinels below, so
+// callers can match the kind of failure with errors.Is and get the operation
+// and its arguments with errors.As.
+package safemath
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrNaN is an argument that is not a number.
+	ErrNaN = errors.New("argument is NaN")
+	// ErrInfinite is an argument that is ±Inf.
+	ErrInfinite = errors.New("argument is infinite")
+	// ErrDomain is an argument for which the function is not defined, like
+	// the square root of a negative number.
+	ErrDomain = errors.New("argument out of domain")
+	// ErrPole is an argument where the function goes to infinity, like the
+	// logarithm of zero.
+	ErrPole = errors.New("pole error")
+	// ErrOverflow is a result too large for its type.
+	ErrOverflow = errors.New("result overflows")
+	// ErrDivByZero is an integer division by zero.
+	ErrDivByZero = errors.New("division by zero")
+)
+
+// Error records the operation that failed and why.
+type Error struct {
+	Op   string
+	Args []any
+	Err  error
+}
+
+func (e *Error) Error() string {
+	args := make([]string, len(e.Args))
+	for i, arg := range e.Args {
+		args[i] = fmt.Sprint(arg)
+	}
+	return "safemath: " + e.Op + "(" + strings.Join(args, ", ") + "): " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error { return e.Err }
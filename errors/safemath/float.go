@@ -0,0 +1,92 @@
+package safemath
+
+import "math"
+
+// checkFinite returns the error for the first argument that is NaN or
+// infinite.
+func checkFinite(op string, args ...float64) error {
+	for _, x := range args {
+		switch {
+		case math.IsNaN(x):
+			return newError(op, ErrNaN, args...)
+		case math.IsInf(x, 0):
+			return newError(op, ErrInfinite, args...)
+		}
+	}
+	return nil
+}
+
+func newError(op string, err error, args ...float64) *Error {
+	e := &Error{Op: op, Err: err}
+	for _, x := range args {
+		e.Args = append(e.Args, x)
+	}
+	return e
+}
+
+// Sqrt returns the square root of x, or ErrDomain if x is negative.
+func Sqrt(x float64) (float64, error) {
+	if err := checkFinite("Sqrt", x); err != nil {
+		return 0, err
+	}
+	if x < 0 {
+		return 0, newError("Sqrt", ErrDomain, x)
+	}
+	return math.Sqrt(x), nil
+}
+
+// Log returns the natural logarithm of x, ErrPole if x is zero or
+// ErrDomain if x is negative.
+func Log(x float64) (float64, error) {
+	if err := checkFinite("Log", x); err != nil {
+		return 0, err
+	}
+	switch {
+	case x == 0:
+		return 0, newError("Log", ErrPole, x)
+	case x < 0:
+		return 0, newError("Log", ErrDomain, x)
+	}
+	return math.Log(x), nil
+}
+
+// Pow returns x**y. It fails with ErrDomain for a negative x and a
+// non-integer y, ErrPole for zero raised to a negative power and
+// ErrOverflow when the result doesn't fit a float64.
+func Pow(x, y float64) (float64, error) {
+	if err := checkFinite("Pow", x, y); err != nil {
+		return 0, err
+	}
+	r := math.Pow(x, y)
+	switch {
+	case math.IsNaN(r):
+		return 0, newError("Pow", ErrDomain, x, y)
+	case math.IsInf(r, 0) && x == 0:
+		return 0, newError("Pow", ErrPole, x, y)
+	case math.IsInf(r, 0):
+		return 0, newError("Pow", ErrOverflow, x, y)
+	}
+	return r, nil
+}
+
+// Asin returns the arcsine of x, or ErrDomain if x is outside [-1, 1].
+func Asin(x float64) (float64, error) {
+	if err := checkFinite("Asin", x); err != nil {
+		return 0, err
+	}
+	if x < -1 || x > 1 {
+		return 0, newError("Asin", ErrDomain, x)
+	}
+	return math.Asin(x), nil
+}
+
+// Acos returns the arccosine of x, or ErrDomain if x is outside [-1, 1].
+func Acos(x float64) (float64, error) {
+	if err := checkFinite("Acos", x); err != nil {
+		return 0, err
+	}
+	if x < -1 || x > 1 {
+		return 0, newError("Acos", ErrDomain, x)
+	}
+	return math.Acos(x), nil
+}
@@ -0,0 +1,70 @@
+package safemath
+
+import "unsafe"
+
+// Integer is any integer type.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+func intError[T Integer](op string, err error, a, b T) *Error {
+	return &Error{Op: op, Args: []any{a, b}, Err: err}
+}
+
+func signed[T Integer]() bool {
+	var zero T
+	return zero-1 < zero
+}
+
+// minSigned returns the most negative value of a signed T.
+func minSigned[T Integer]() T {
+	var zero T
+	return T(1) << (unsafe.Sizeof(zero)*8 - 1)
+}
+
+// Add returns a + b, or ErrOverflow if the sum wraps around.
+func Add[T Integer](a, b T) (T, error) {
+	c := a + b
+	if signed[T]() {
+		if (b > 0 && c < a) || (b < 0 && c > a) {
+			return 0, intError("Add", ErrOverflow, a, b)
+		}
+	} else if c < a {
+		return 0, intError("Add", ErrOverflow, a, b)
+	}
+	return c, nil
+}
+
+// Mul returns a * b, or ErrOverflow if the product wraps around.
+func Mul[T Integer](a, b T) (T, error) {
+	if a == 0 || b == 0 {
+		return 0, nil
+	}
+	if signed[T]() {
+		// MinInt * -1 wraps to MinInt, and the division check below
+		// can't see it, because MinInt / -1 wraps the same way.
+		lowest := minSigned[T]()
+		if (a == lowest && b == ^T(0)) || (b == lowest && a == ^T(0)) {
+			return 0, intError("Mul", ErrOverflow, a, b)
+		}
+	}
+	c := a * b
+	if c/b != a {
+		return 0, intError("Mul", ErrOverflow, a, b)
+	}
+	return c, nil
+}
+
+// Div returns a / b truncated toward zero. It fails with ErrDivByZero
+// instead of panicking, and with ErrOverflow for MinInt / -1, whose result
+// would silently wrap to MinInt.
+func Div[T Integer](a, b T) (T, error) {
+	if b == 0 {
+		return 0, intError("Div", ErrDivByZero, a, b)
+	}
+	if signed[T]() && a == minSigned[T]() && b == ^T(0) {
+		return 0, intError("Div", ErrOverflow, a, b)
+	}
+	return a / b, nil
+}
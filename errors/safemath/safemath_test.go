@@ -0,0 +1,248 @@
+package safemath_test
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/salmomascarenhas/go-study-exercises/errors/safemath"
+)
+
+// checkFloat fails the test unless err is a *safemath.Error for op
+// matching want, or nil if want is nil. A nil error must come with a finite
+// result.
+func checkFloat(t *testing.T, op string, got float64, err, want error) {
+	t.Helper()
+	if want == nil {
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", op, err)
+		}
+		if math.IsNaN(got) || math.IsInf(got, 0) {
+			t.Fatalf("%s: succeeded with %v", op, got)
+		}
+		return
+	}
+	if !errors.Is(err, want) {
+		t.Fatalf("%s: got error %v, want %v", op, err, want)
+	}
+	var e *safemath.Error
+	if !errors.As(err, &e) || e.Op != op {
+		t.Fatalf("%s: got %#v, want a *safemath.Error for %s", op, err, op)
+	}
+}
+
+// finiteErr returns the error every float function returns first for a NaN
+// or infinite argument.
+func finiteErr(args ...float64) error {
+	for _, x := range args {
+		switch {
+		case math.IsNaN(x):
+			return safemath.ErrNaN
+		case math.IsInf(x, 0):
+			return safemath.ErrInfinite
+		}
+	}
+	return nil
+}
+
+var floatSeeds = []float64{0, math.Copysign(0, -1), 1, -1, 0.5, -0.5, 4, -4, 1e308, -1e308,
+	math.SmallestNonzeroFloat64, math.NaN(), math.Inf(1), math.Inf(-1)}
+
+func FuzzSqrt(f *testing.F) {
+	for _, x := range floatSeeds {
+		f.Add(x)
+	}
+	f.Fuzz(func(t *testing.T, x float64) {
+		got, err := safemath.Sqrt(x)
+		want := finiteErr(x)
+		if want == nil && x < 0 {
+			want = safemath.ErrDomain
+		}
+		checkFloat(t, "Sqrt", got, err, want)
+		if err == nil && got != math.Sqrt(x) {
+			t.Fatalf("Sqrt(%v) = %v, want %v", x, got, math.Sqrt(x))
+		}
+	})
+}
+
+func FuzzLog(f *testing.F) {
+	for _, x := range floatSeeds {
+		f.Add(x)
+	}
+	f.Fuzz(func(t *testing.T, x float64) {
+		got, err := safemath.Log(x)
+		want := finiteErr(x)
+		switch {
+		case want != nil:
+		case x == 0:
+			want = safemath.ErrPole
+		case x < 0:
+			want = safemath.ErrDomain
+		}
+		checkFloat(t, "Log", got, err, want)
+		if err == nil && got != math.Log(x) {
+			t.Fatalf("Log(%v) = %v, want %v", x, got, math.Log(x))
+		}
+	})
+}
+
+func FuzzPow(f *testing.F) {
+	for _, x := range floatSeeds {
+		f.Add(x, 2.0)
+		f.Add(x, -1.0)
+		f.Add(x, 0.5)
+	}
+	f.Add(10.0, 400.0)
+	f.Fuzz(func(t *testing.T, x, y float64) {
+		got, err := safemath.Pow(x, y)
+		want := finiteErr(x, y)
+		if want == nil {
+			switch r := math.Pow(x, y); {
+			case x < 0 && y != math.Trunc(y):
+				want = safemath.ErrDomain
+			case x == 0 && y < 0:
+				want = safemath.ErrPole
+			case math.IsInf(r, 0):
+				want = safemath.ErrOverflow
+			}
+		}
+		checkFloat(t, "Pow", got, err, want)
+		if err == nil && got != math.Pow(x, y) {
+			t.Fatalf("Pow(%v, %v) = %v, want %v", x, y, got, math.Pow(x, y))
+		}
+	})
+}
+
+func fuzzInverseTrig(f *testing.F, op string, fn func(float64) (float64, error), ref func(float64) float64, lo, hi float64) {
+	for _, x := range floatSeeds {
+		f.Add(x)
+	}
+	f.Add(math.Nextafter(1, 2))
+	f.Add(math.Nextafter(-1, -2))
+	f.Fuzz(func(t *testing.T, x float64) {
+		got, err := fn(x)
+		want := finiteErr(x)
+		if want == nil && (x < -1 || x > 1) {
+			want = safemath.ErrDomain
+		}
+		checkFloat(t, op, got, err, want)
+		if err != nil {
+			return
+		}
+		if r := ref(x); got != r || got < lo || got > hi {
+			t.Fatalf("%s(%v) = %v, want %v in [%v, %v]", op, x, got, r, lo, hi)
+		}
+	})
+}
+
+func FuzzAsin(f *testing.F) {
+	fuzzInverseTrig(f, "Asin", safemath.Asin, math.Asin, -math.Pi/2, math.Pi/2)
+}
+
+func FuzzAcos(f *testing.F) {
+	fuzzInverseTrig(f, "Acos", safemath.Acos, math.Acos, 0, math.Pi)
+}
+
+func toBig[T safemath.Integer](v T) *big.Int {
+	var zero T
+	if zero-1 < zero {
+		return big.NewInt(int64(v))
+	}
+	return new(big.Int).SetUint64(uint64(v))
+}
+
+// checkInt checks the result of an integer operation against want, the
+// exact result computed with math/big. wrapped is the result computed in
+// T, which differs from want exactly when the operation overflows.
+func checkInt[T safemath.Integer](t *testing.T, op string, a, b, got T, err error, want *big.Int, wrapped T) {
+	t.Helper()
+	if toBig(wrapped).Cmp(want) == 0 {
+		if err != nil || got != wrapped {
+			t.Fatalf("%s(%v, %v) = %v, %v; want %v", op, a, b, got, err, want)
+		}
+		return
+	}
+	if !errors.Is(err, safemath.ErrOverflow) {
+		t.Fatalf("%s(%v, %v) = %v, %v; want ErrOverflow, since the result is %v", op, a, b, got, err, want)
+	}
+}
+
+func FuzzAdd(f *testing.F) {
+	f.Add(int64(1), int64(2))
+	f.Add(int64(math.MaxInt64), int64(1))
+	f.Add(int64(math.MinInt64), int64(-1))
+	f.Add(int64(-1), int64(1))
+	f.Fuzz(func(t *testing.T, a, b int64) {
+		add := func(x, y *big.Int) *big.Int { return new(big.Int).Add(x, y) }
+		got, err := safemath.Add(a, b)
+		checkInt(t, "Add", a, b, got, err, add(toBig(a), toBig(b)), a+b)
+
+		ua, ub := uint64(a), uint64(b)
+		ugot, err := safemath.Add(ua, ub)
+		checkInt(t, "Add", ua, ub, ugot, err, add(toBig(ua), toBig(ub)), ua+ub)
+
+		sa, sb := int8(a), int8(b)
+		sgot, err := safemath.Add(sa, sb)
+		checkInt(t, "Add", sa, sb, sgot, err, big.NewInt(int64(sa)+int64(sb)), sa+sb)
+	})
+}
+
+func FuzzMul(f *testing.F) {
+	f.Add(int64(3), int64(4))
+	f.Add(int64(0), int64(math.MinInt64))
+	f.Add(int64(math.MinInt64), int64(-1))
+	f.Add(int64(-1), int64(math.MinInt64))
+	f.Add(int64(1)<<32, int64(1)<<31)
+	f.Fuzz(func(t *testing.T, a, b int64) {
+		mul := func(x, y *big.Int) *big.Int { return new(big.Int).Mul(x, y) }
+		got, err := safemath.Mul(a, b)
+		checkInt(t, "Mul", a, b, got, err, mul(toBig(a), toBig(b)), a*b)
+
+		ua, ub := uint64(a), uint64(b)
+		ugot, err := safemath.Mul(ua, ub)
+		checkInt(t, "Mul", ua, ub, ugot, err, mul(toBig(ua), toBig(ub)), ua*ub)
+
+		sa, sb := int8(a), int8(b)
+		sgot, err := safemath.Mul(sa, sb)
+		checkInt(t, "Mul", sa, sb, sgot, err, big.NewInt(int64(sa)*int64(sb)), sa*sb)
+	})
+}
+
+func FuzzDiv(f *testing.F) {
+	f.Add(int64(7), int64(2))
+	f.Add(int64(-7), int64(2))
+	f.Add(int64(1), int64(0))
+	f.Add(int64(math.MinInt64), int64(-1))
+	f.Fuzz(func(t *testing.T, a, b int64) {
+		if b == 0 {
+			for _, err := range []error{
+				second(safemath.Div(a, b)),
+				second(safemath.Div(uint64(a), uint64(b))),
+				second(safemath.Div(int8(a), int8(b))),
+			} {
+				if !errors.Is(err, safemath.ErrDivByZero) {
+					t.Fatalf("Div(%v, 0): got %v, want ErrDivByZero", a, err)
+				}
+			}
+			return
+		}
+		// big.Int.Quo truncates toward zero, like Go's /.
+		quo := func(x, y *big.Int) *big.Int { return new(big.Int).Quo(x, y) }
+		got, err := safemath.Div(a, b)
+		checkInt(t, "Div", a, b, got, err, quo(toBig(a), toBig(b)), a/b)
+
+		ua, ub := uint64(a), uint64(b)
+		ugot, err := safemath.Div(ua, ub)
+		checkInt(t, "Div", ua, ub, ugot, err, quo(toBig(ua), toBig(ub)), ua/ub)
+
+		sa, sb := int8(a), int8(b)
+		if sb == 0 {
+			return
+		}
+		sgot, err := safemath.Div(sa, sb)
+		checkInt(t, "Div", sa, sb, sgot, err, quo(toBig(sa), toBig(sb)), sa/sb)
+	})
+}
+
+func second[T any](_ T, err error) error { return err }
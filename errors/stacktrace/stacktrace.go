@@ -0,0 +1,152 @@
+// Package stacktrace builds errors that remember where they were created.
+//
+// Formatting such an error with %v or %s prints only the message; %+v also
+// prints the call stack, one frame per line as "function" followed by
+// "file:line", and then the stacks of the errors it wraps, errors.Join
+// branches included. The errors work with errors.Is, errors.As and
+// errors.Unwrap like any other wrapped error.
+package stacktrace
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+)
+
+const maxDepth = 32
+
+// Frame is one function call of a stack trace.
+type Frame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+func (f Frame) String() string {
+	return f.Function + "\n\t" + f.File + ":" + strconv.Itoa(f.Line)
+}
+
+// StackTracer is implemented by the errors of this package. Use it with
+// errors.As to reach the stack of a wrapped error.
+type StackTracer interface {
+	error
+	StackTrace() []Frame
+}
+
+type withStack struct {
+	err   error
+	stack []uintptr
+}
+
+// New returns an error with the text msg and the stack of its caller.
+func New(msg string) error {
+	return &withStack{err: errors.New(msg), stack: callers()}
+}
+
+// Errorf is fmt.Errorf plus the stack of its caller. %w verbs wrap as
+// usual.
+func Errorf(format string, args ...any) error {
+	return &withStack{err: fmt.Errorf(format, args...), stack: callers()}
+}
+
+// Wrap annotates err with msg and the stack of its caller. Wrap returns nil
+// if err is nil.
+func Wrap(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	return &withStack{err: fmt.Errorf("%s: %w", msg, err), stack: callers()}
+}
+
+// WithStack records the stack of its caller on err without changing its
+// message. WithStack returns nil if err is nil.
+func WithStack(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &withStack{err: err, stack: callers()}
+}
+
+// Join is errors.Join plus the stack of its caller. %+v prints the stacks
+// of every branch under it.
+func Join(errs ...error) error {
+	err := errors.Join(errs...)
+	if err == nil {
+		return nil
+	}
+	return &withStack{err: err, stack: callers()}
+}
+
+// callers captures the stack above the exported constructor that called it.
+func callers() []uintptr {
+	pcs := make([]uintptr, maxDepth)
+	n := runtime.Callers(3, pcs)
+	return pcs[:n]
+}
+
+func (e *withStack) Error() string { return e.err.Error() }
+
+func (e *withStack) Unwrap() error { return e.err }
+
+func (e *withStack) StackTrace() []Frame {
+	frames := runtime.CallersFrames(e.stack)
+	var out []Frame
+	for {
+		f, more := frames.Next()
+		out = append(out, Frame{Function: f.Function, File: f.File, Line: f.Line})
+		if !more {
+			return out
+		}
+	}
+}
+
+// Format implements fmt.Formatter: %s and %v print the message, %q a quoted
+// message and %+v the message followed by the stack traces.
+func (e *withStack) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			io.WriteString(s, e.Error())
+			e.writeStack(s)
+			for _, cause := range tracedCauses(e.err) {
+				fmt.Fprintf(s, "\n\ncaused by: %+v", cause)
+			}
+			return
+		}
+		io.WriteString(s, e.Error())
+	case 's':
+		io.WriteString(s, e.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	}
+}
+
+func (e *withStack) writeStack(w io.Writer) {
+	for _, f := range e.StackTrace() {
+		io.WriteString(w, "\n"+f.String())
+	}
+}
+
+// tracedCauses returns the nearest errors under err, on every branch of its
+// tree, that carry a stack of their own.
+func tracedCauses(err error) []error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(StackTracer); ok { //errlint:ignore only err itself, not what it wraps
+		return []error{err}
+	}
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		return tracedCauses(u.Unwrap())
+	case interface{ Unwrap() []error }:
+		var causes []error
+		for _, branch := range u.Unwrap() {
+			causes = append(causes, tracedCauses(branch)...)
+		}
+		return causes
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package stacktrace_test
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/salmomascarenhas/go-study-exercises/errors/stacktrace"
+)
+
+// line returns the line it is called from, to compare with the frames of
+// an error built on the same line.
+func line() int {
+	_, _, n, _ := runtime.Caller(1)
+	return n
+}
+
+// wantFrame fails t unless trace holds a frame for the test function fn
+// at stacktrace_test.go:n.
+func wantFrame(t *testing.T, trace string, fn string, n int) {
+	t.Helper()
+	function := "stacktrace_test." + fn + "\n"
+	location := "stacktrace_test.go:" + strconv.Itoa(n) + "\n"
+	if !strings.Contains(trace, function) || !strings.Contains(trace+"\n", location) {
+		t.Errorf("trace has no frame %s at %s:\n%s", fn, strings.TrimSpace(location), trace)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	err, n := stacktrace.New("boom"), line()
+
+	if got := fmt.Sprintf("%v", err); got != "boom" {
+		t.Errorf("%%v = %q, want %q", got, "boom")
+	}
+	if got := fmt.Sprintf("%s", err); got != "boom" {
+		t.Errorf("%%s = %q, want %q", got, "boom")
+	}
+	if got := fmt.Sprintf("%q", err); got != `"boom"` {
+		t.Errorf("%%q = %q, want %q", got, `"boom"`)
+	}
+
+	trace := fmt.Sprintf("%+v", err)
+	if !strings.HasPrefix(trace, "boom\n") {
+		t.Errorf("%%+v doesn't start with the message:\n%s", trace)
+	}
+	wantFrame(t, trace, "TestFormat", n)
+}
+
+func TestStackTrace(t *testing.T) {
+	err, n := stacktrace.Errorf("read %s: %w", "x", fs.ErrNotExist), line()
+	var tracer stacktrace.StackTracer
+	if !errors.As(fmt.Errorf("outer: %w", err), &tracer) {
+		t.Fatal("errors.As found no StackTracer")
+	}
+	frames := tracer.StackTrace()
+	if len(frames) == 0 {
+		t.Fatal("empty stack trace")
+	}
+	if top := frames[0]; !strings.HasSuffix(top.Function, ".TestStackTrace") || !strings.HasSuffix(top.File, "stacktrace_test.go") || top.Line != n {
+		t.Errorf("top frame = %+v, want TestStackTrace at stacktrace_test.go:%d", top, n)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	if err := stacktrace.Wrap(nil, "context"); err != nil {
+		t.Errorf("Wrap(nil) = %v, want nil", err)
+	}
+
+	err, n := stacktrace.Wrap(fs.ErrNotExist, "open config"), line()
+	if got, want := err.Error(), "open config: "+fs.ErrNotExist.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Error("errors.Is doesn't see the wrapped error")
+	}
+	var pathErr *fs.PathError
+	wrapped := stacktrace.Wrap(&fs.PathError{Op: "open", Path: "a.txt", Err: fs.ErrPermission}, "load")
+	if !errors.As(wrapped, &pathErr) || pathErr.Path != "a.txt" {
+		t.Errorf("errors.As(%v) found %v, want the *fs.PathError", wrapped, pathErr)
+	}
+	if !errors.Is(wrapped, fs.ErrPermission) {
+		t.Error("errors.Is doesn't see through the *fs.PathError")
+	}
+	wantFrame(t, fmt.Sprintf("%+v", err), "TestWrap", n)
+}
+
+func TestWithStack(t *testing.T) {
+	if err := stacktrace.WithStack(nil); err != nil {
+		t.Errorf("WithStack(nil) = %v, want nil", err)
+	}
+
+	err, n := stacktrace.WithStack(fs.ErrClosed), line()
+	if err.Error() != fs.ErrClosed.Error() {
+		t.Errorf("WithStack changed the message to %q", err)
+	}
+	if !errors.Is(err, fs.ErrClosed) || errors.Unwrap(err) != fs.ErrClosed { //errlint:ignore checks the direct cause
+		t.Error("WithStack doesn't wrap its error")
+	}
+	wantFrame(t, fmt.Sprintf("%+v", err), "TestWithStack", n)
+}
+
+func TestCausedBy(t *testing.T) {
+	inner, innerLine := stacktrace.New("disk full"), line()
+	outer, outerLine := stacktrace.Wrap(inner, "save"), line()
+
+	trace := fmt.Sprintf("%+v", outer)
+	before, after, ok := strings.Cut(trace, "\n\ncaused by: ")
+	if !ok {
+		t.Fatalf("no caused by section:\n%s", trace)
+	}
+	wantFrame(t, before, "TestCausedBy", outerLine)
+	if !strings.HasPrefix(after, "disk full\n") {
+		t.Errorf("caused by doesn't start with the inner message:\n%s", after)
+	}
+	wantFrame(t, after, "TestCausedBy", innerLine)
+}
+
+func TestJoin(t *testing.T) {
+	if err := stacktrace.Join(nil, nil); err != nil {
+		t.Errorf("Join(nil, nil) = %v, want nil", err)
+	}
+
+	a, aLine := stacktrace.New("a failed"), line()
+	b, bLine := stacktrace.New("b failed"), line()
+	plain := errors.New("c failed")
+	err, n := stacktrace.Join(a, fmt.Errorf("wrapped: %w", b), plain), line()
+
+	if got, want := err.Error(), "a failed\nwrapped: b failed\nc failed"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	for _, target := range []error{a, b, plain} {
+		if !errors.Is(err, target) {
+			t.Errorf("errors.Is(join, %v) = false", target)
+		}
+	}
+
+	sections := strings.Split(fmt.Sprintf("%+v", err), "\n\ncaused by: ")
+	if len(sections) != 3 {
+		t.Fatalf("got %d sections, want the join and one caused by per traced branch:\n%s",
+			len(sections), strings.Join(sections, "\n\ncaused by: "))
+	}
+	wantFrame(t, sections[0], "TestJoin", n)
+	for i, want := range []struct {
+		msg  string
+		line int
+	}{{"a failed", aLine}, {"b failed", bLine}} {
+		if !strings.HasPrefix(sections[i+1], want.msg+"\n") {
+			t.Errorf("caused by %d = %q..., want %q", i+1, strings.SplitN(sections[i+1], "\n", 2)[0], want.msg)
+		}
+		wantFrame(t, sections[i+1], "TestJoin", want.line)
+	}
+}
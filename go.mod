@@ -1,3 +1,8 @@
 module github.com/salmomascarenhas/go-study-exercises
 
 go 1.22.4
+
+require (
+	github.com/BurntSushi/toml v1.4.0
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLocale is used whenever the requested locale, or a key inside it,
+// has no translation.
+const DefaultLocale = "en"
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// Gender selects the gendered variant of a message. The zero value is
+// Neutral, which always maps to the default ("other") text.
+type Gender int
+
+const (
+	Neutral Gender = iota
+	Feminine
+	Masculine
+)
+
+func (g Gender) String() string {
+	switch g {
+	case Feminine:
+		return "feminine"
+	case Masculine:
+		return "masculine"
+	default:
+		return "neutral"
+	}
+}
+
+// Message holds the variants of one translated string. Keys are a plural
+// category ("zero", "one", "other"), a gender ("feminine", "masculine") or
+// both joined by a dot ("feminine.one"). "other" is the gender-neutral
+// default and must always be present.
+//
+// In the locale files a message can be written as a plain string, which is
+// shorthand for {"other": "..."}.
+type Message map[string]string
+
+func (m *Message) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*m = Message{"other": text}
+		return nil
+	}
+	variants := map[string]string{}
+	if err := json.Unmarshal(data, &variants); err != nil {
+		return err
+	}
+	if _, ok := variants["other"]; !ok {
+		return fmt.Errorf("message has no \"other\" variant")
+	}
+	*m = variants
+	return nil
+}
+
+// Catalog maps locale tags (e.g. "pt-BR") to their messages.
+type Catalog struct {
+	messages map[string]map[string]Message
+}
+
+// LoadCatalog reads every *.json file at the root of fsys. The file name,
+// without extension, is the locale tag.
+func LoadCatalog(fsys fs.FS) (*Catalog, error) {
+	files, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return nil, err
+	}
+	c := &Catalog{messages: make(map[string]map[string]Message)}
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		var messages map[string]Message
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return nil, fmt.Errorf("locale %s: %w", file, err)
+		}
+		c.messages[normalizeLocale(strings.TrimSuffix(path.Base(file), ".json"))] = messages
+	}
+	return c, nil
+}
+
+// MustLoadCatalog is like LoadCatalog but panics on error. It is meant for
+// the embedded locales, which are known to be valid at build time.
+func MustLoadCatalog(fsys fs.FS) *Catalog {
+	c, err := LoadCatalog(fsys)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// EmbeddedLocales returns the locale files shipped with the binary.
+func EmbeddedLocales() fs.FS {
+	sub, err := fs.Sub(localeFiles, "locales")
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
+
+// Locales lists the locale tags known to the catalog, sorted.
+func (c *Catalog) Locales() []string {
+	locales := make([]string, 0, len(c.messages))
+	for locale := range c.messages {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Localizer picks a fallback chain for tag and returns a Localizer that
+// renders messages through it. The chain is the exact tag, its base
+// language, any other region of that language and finally DefaultLocale,
+// keeping only the locales the catalog actually has.
+func (c *Catalog) Localizer(tag string) *Localizer {
+	tag = normalizeLocale(tag)
+	lang := baseLanguage(tag)
+
+	var chain []string
+	add := func(locale string) {
+		if _, ok := c.messages[locale]; !ok {
+			return
+		}
+		for _, l := range chain {
+			if l == locale {
+				return
+			}
+		}
+		chain = append(chain, locale)
+	}
+	add(tag)
+	add(lang)
+	for _, locale := range c.Locales() {
+		if baseLanguage(locale) == lang {
+			add(locale)
+		}
+	}
+	add(DefaultLocale)
+	return &Localizer{catalog: c, chain: chain}
+}
+
+// Args carries the values a message is rendered with.
+type Args struct {
+	// Count selects the plural form and is available as {count}.
+	Count int
+	// Gender selects the gendered form; Neutral uses the default text.
+	Gender Gender
+	// Vars fills the {name} style placeholders.
+	Vars map[string]string
+}
+
+// Localizer renders messages for one locale, walking its fallback chain
+// when a key is missing.
+type Localizer struct {
+	catalog *Catalog
+	chain   []string
+}
+
+// Locale returns the locale messages are primarily rendered in.
+func (l *Localizer) Locale() string {
+	if len(l.chain) == 0 {
+		return DefaultLocale
+	}
+	return l.chain[0]
+}
+
+// Translate renders key with args. If no locale in the chain has the key,
+// the key itself is returned so missing translations are easy to spot.
+func (l *Localizer) Translate(key string, args Args) string {
+	for _, locale := range l.chain {
+		msg, ok := l.catalog.messages[locale][key]
+		if !ok {
+			continue
+		}
+		return render(msg.variant(locale, args.Count, args.Gender), args)
+	}
+	return key
+}
+
+// variant picks the most specific text available for count and gender. An
+// explicit "zero" form wins over the language's plural rule when count is 0.
+func (m Message) variant(locale string, count int, gender Gender) string {
+	plurals := []string{pluralCategory(locale, count)}
+	if count == 0 {
+		plurals = append([]string{"zero"}, plurals...)
+	}
+	var candidates []string
+	for _, plural := range plurals {
+		if gender != Neutral {
+			candidates = append(candidates, gender.String()+"."+plural)
+		}
+	}
+	if gender != Neutral {
+		candidates = append(candidates, gender.String())
+	}
+	candidates = append(candidates, plurals...)
+	candidates = append(candidates, "other")
+	for _, key := range candidates {
+		if text, ok := m[key]; ok {
+			return text
+		}
+	}
+	return ""
+}
+
+func render(text string, args Args) string {
+	pairs := []string{"{count}", strconv.Itoa(args.Count)}
+	for k, v := range args.Vars {
+		pairs = append(pairs, "{"+k+"}", v)
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+// pluralCategory implements the CLDR cardinal rules for the languages in
+// the catalog.
+func pluralCategory(locale string, n int) string {
+	switch baseLanguage(locale) {
+	case "pt":
+		if n == 0 || n == 1 {
+			return "one"
+		}
+	default:
+		if n == 1 {
+			return "one"
+		}
+	}
+	return "other"
+}
+
+// LocaleFromEnv returns the user's locale following the POSIX precedence of
+// LC_ALL, LC_MESSAGES and LANG. "C" and "POSIX" mean no preference.
+func LocaleFromEnv() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			if value == "C" || value == "POSIX" {
+				return DefaultLocale
+			}
+			return normalizeLocale(value)
+		}
+	}
+	return DefaultLocale
+}
+
+// normalizeLocale turns a BCP 47 tag or a POSIX locale ("pt_BR.UTF-8")
+// into the canonical form used by the catalog ("pt-BR").
+func normalizeLocale(tag string) string {
+	if i := strings.IndexAny(tag, ".@"); i >= 0 {
+		tag = tag[:i]
+	}
+	parts := strings.Split(strings.ReplaceAll(tag, "_", "-"), "-")
+	parts[0] = strings.ToLower(parts[0])
+	for i := 1; i < len(parts); i++ {
+		if len(parts[i]) == 2 {
+			parts[i] = strings.ToUpper(parts[i])
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+func baseLanguage(tag string) string {
+	lang, _, _ := strings.Cut(tag, "-")
+	return lang
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLocalizerFallback(t *testing.T) {
+	c, err := LoadCatalog(fstest.MapFS{
+		"en.json":    {Data: []byte(`{"a": "A en", "b": "B en", "c": "C en"}`)},
+		"pt.json":    {Data: []byte(`{"a": "A pt"}`)},
+		"pt-BR.json": {Data: []byte(`{"a": "A pt-BR", "c": "C pt-BR"}`)},
+		"notes.txt":  {Data: []byte("not a locale")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := c.Locales(), []string{"en", "pt", "pt-BR"}; !slices.Equal(got, want) {
+		t.Errorf("Locales = %q, want %q", got, want)
+	}
+
+	tests := []struct {
+		tag    string
+		locale string
+		want   map[string]string
+	}{
+		// pt-BR, then pt, then en.
+		{"pt_BR.UTF-8", "pt-BR", map[string]string{"a": "A pt-BR", "b": "B en", "c": "C pt-BR", "d": "d"}},
+		// No pt-PT: pt, then the other pt region, then en.
+		{"pt-PT", "pt", map[string]string{"a": "A pt", "b": "B en", "c": "C pt-BR", "d": "d"}},
+		{"fr", "en", map[string]string{"a": "A en", "b": "B en", "c": "C en", "d": "d"}},
+		{"", "en", map[string]string{"a": "A en"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			l := c.Localizer(tt.tag)
+			if l.Locale() != tt.locale {
+				t.Errorf("Locale = %q, want %q", l.Locale(), tt.locale)
+			}
+			for key, want := range tt.want {
+				if got := l.Translate(key, Args{}); got != want {
+					t.Errorf("Translate(%q) = %q, want %q", key, got, want)
+				}
+			}
+		})
+	}
+
+	empty, err := LoadCatalog(fstest.MapFS{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l := empty.Localizer("pt-BR"); l.Locale() != DefaultLocale || l.Translate("a", Args{}) != "a" {
+		t.Errorf("empty catalog: Locale %q, Translate %q", l.Locale(), l.Translate("a", Args{}))
+	}
+}
+
+func TestEmbeddedLocalizerFallback(t *testing.T) {
+	c := MustLoadCatalog(EmbeddedLocales())
+	tests := map[string]string{
+		"pt-PT": "pt-BR",
+		"pt":    "pt-BR",
+		"pt-br": "pt-BR",
+		"es_AR": "es",
+		"fr":    "en",
+	}
+	for tag, want := range tests {
+		if got := c.Localizer(tag).Locale(); got != want {
+			t.Errorf("Localizer(%q).Locale() = %q, want %q", tag, got, want)
+		}
+	}
+	if got := c.Localizer("pt-PT").Translate("greeting", Args{Vars: map[string]string{"name": "Ana"}}); got != "Olá, meu nome é Ana" {
+		t.Errorf("pt-PT greeting = %q, want the pt-BR one", got)
+	}
+	if got := c.Localizer("pt-BR").Translate("no.such.key", Args{}); got != "no.such.key" {
+		t.Errorf("missing key = %q, want the key itself", got)
+	}
+}
+
+func TestTranslatePlurals(t *testing.T) {
+	c := MustLoadCatalog(EmbeddedLocales())
+	tests := []struct {
+		locale string
+		count  int
+		want   string
+	}{
+		{"en", 0, "no people"},
+		{"en", 1, "1 person"},
+		{"en", 2, "2 people"},
+		{"pt-BR", 0, "nenhuma pessoa"},
+		{"pt-BR", 1, "1 pessoa"},
+		{"pt-BR", 2, "2 pessoas"},
+		{"es", 0, "ninguna persona"},
+		{"es", 1, "1 persona"},
+		{"es", 21, "21 personas"},
+	}
+	for _, tt := range tests {
+		if got := c.Localizer(tt.locale).Translate("people", Args{Count: tt.count}); got != tt.want {
+			t.Errorf("%s: people with count %d = %q, want %q", tt.locale, tt.count, got, tt.want)
+		}
+	}
+}
+
+func TestTranslateGender(t *testing.T) {
+	c := MustLoadCatalog(EmbeddedLocales())
+	tests := []struct {
+		locale string
+		gender Gender
+		want   string
+	}{
+		{"pt-BR", Feminine, "Bem-vinda, Alex"},
+		{"pt-BR", Masculine, "Bem-vindo, Alex"},
+		{"pt-BR", Neutral, "Boas-vindas, Alex"},
+		{"es", Feminine, "Bienvenida, Alex"},
+		{"es", Neutral, "Te damos la bienvenida, Alex"},
+		// English has no gendered forms: every gender gets the default.
+		{"en", Feminine, "Welcome, Alex"},
+		{"en", Masculine, "Welcome, Alex"},
+		{"en", Neutral, "Welcome, Alex"},
+	}
+	for _, tt := range tests {
+		args := Args{Gender: tt.gender, Vars: map[string]string{"name": "Alex"}}
+		if got := c.Localizer(tt.locale).Translate("welcome", args); got != tt.want {
+			t.Errorf("%s: welcome %s = %q, want %q", tt.locale, tt.gender, got, tt.want)
+		}
+	}
+}
+
+func TestPluralCategory(t *testing.T) {
+	tests := []struct {
+		locale string
+		n      int
+		want   string
+	}{
+		{"en", 0, "other"},
+		{"en", 1, "one"},
+		{"en", 2, "other"},
+		{"es", 0, "other"},
+		{"es", 1, "one"},
+		// Portuguese treats 0 like 1.
+		{"pt-BR", 0, "one"},
+		{"pt", 1, "one"},
+		{"pt-PT", 2, "other"},
+	}
+	for _, tt := range tests {
+		if got := pluralCategory(tt.locale, tt.n); got != tt.want {
+			t.Errorf("pluralCategory(%q, %d) = %q, want %q", tt.locale, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestMessageVariant(t *testing.T) {
+	m := Message{
+		"other":        "other",
+		"zero":         "zero",
+		"one":          "one",
+		"feminine":     "feminine",
+		"feminine.one": "feminine.one",
+	}
+	tests := []struct {
+		locale string
+		count  int
+		gender Gender
+		want   string
+	}{
+		{"en", 0, Neutral, "zero"},
+		{"en", 1, Neutral, "one"},
+		{"en", 2, Neutral, "other"},
+		{"en", 1, Feminine, "feminine.one"},
+		{"en", 2, Feminine, "feminine"},
+		// No feminine.zero: the gender wins over the plural form.
+		{"en", 0, Feminine, "feminine"},
+		// In Portuguese 0 is "one" after "zero".
+		{"pt-BR", 0, Feminine, "feminine.one"},
+		// No masculine variants at all: the plural form.
+		{"en", 1, Masculine, "one"},
+		{"en", 2, Masculine, "other"},
+	}
+	for _, tt := range tests {
+		if got := m.variant(tt.locale, tt.count, tt.gender); got != tt.want {
+			t.Errorf("variant(%q, %d, %s) = %q, want %q", tt.locale, tt.count, tt.gender, got, tt.want)
+		}
+	}
+	if got := (Message{"other": "only"}).variant("pt-BR", 0, Feminine); got != "only" {
+		t.Errorf("a message with only other = %q", got)
+	}
+}
+
+func TestMessageUnmarshal(t *testing.T) {
+	c, err := LoadCatalog(fstest.MapFS{
+		"en.json": {Data: []byte(`{"plain": "text", "forms": {"one": "a", "other": "b"}}`)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.messages["en"]["plain"]; !maps.Equal(got, Message{"other": "text"}) {
+		t.Errorf("plain string = %v, want it as the other variant", got)
+	}
+	if got := c.messages["en"]["forms"]; !maps.Equal(got, Message{"one": "a", "other": "b"}) {
+		t.Errorf("object = %v, want its variants", got)
+	}
+
+	_, err = LoadCatalog(fstest.MapFS{"xx.json": {Data: []byte(`{"k": {"one": "a"}}`)}})
+	if err == nil || !strings.Contains(err.Error(), "xx.json") || !strings.Contains(err.Error(), `"other"`) {
+		t.Errorf("message without other: got %v, want an error naming the file and the variant", err)
+	}
+}
+
+func TestNormalizeLocale(t *testing.T) {
+	tests := map[string]string{
+		"pt_BR.UTF-8": "pt-BR",
+		"pt-br":       "pt-BR",
+		"PT_br":       "pt-BR",
+		"en":          "en",
+		"EN":          "en",
+		"sr_RS@latin": "sr-RS",
+		"zh-Hant-TW":  "zh-Hant-TW",
+		"es-419":      "es-419",
+	}
+	for in, want := range tests {
+		if got := normalizeLocale(in); got != want {
+			t.Errorf("normalizeLocale(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestLocaleFromEnv(t *testing.T) {
+	tests := []struct {
+		lcAll, lcMessages, lang string
+		want                    string
+	}{
+		{"", "", "", DefaultLocale},
+		{"", "", "pt_BR.UTF-8", "pt-BR"},
+		{"", "es_ES.UTF-8", "pt_BR.UTF-8", "es-ES"},
+		{"fr_FR", "es_ES.UTF-8", "pt_BR.UTF-8", "fr-FR"},
+		{"C", "", "pt_BR.UTF-8", DefaultLocale},
+		{"", "POSIX", "pt_BR.UTF-8", DefaultLocale},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lcAll+"|"+tt.lcMessages+"|"+tt.lang, func(t *testing.T) {
+			t.Setenv("LC_ALL", tt.lcAll)
+			t.Setenv("LC_MESSAGES", tt.lcMessages)
+			t.Setenv("LANG", tt.lang)
+			if got := LocaleFromEnv(); got != tt.want {
+				t.Errorf("LocaleFromEnv = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,374 @@
+package main
+
+import (
+	"encoding"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/salmomascarenhas/go-study-exercises/structsinterfaces/inspect"
+	"github.com/salmomascarenhas/go-study-exercises/structsinterfaces/validate"
+)
+
+// Exit codes returned by run, chosen from the type of the error.
+const (
+	exitOK       = 0
+	exitFailure  = 1
+	exitUsage    = 2
+	exitNotFound = 3
+	exitInvalid  = 4
+)
+
+const usage = `usage: structsinterfaces [-file path] <command> [flags] [args]
+
+commands:
+  add     [-format table|json|csv] -name NAME [-email EMAIL] [-phone PHONE]
+          [-birthdate YYYY-MM-DD] [-admin]
+  list    [-format table|json|csv]
+  show    [-format table|json|csv] ID
+  remove  ID
+  greet   [-style STYLE] [-template PATH] ID
+  inspect [-format tree|json] TYPE
+`
+
+// usageError is a command line that doesn't make sense.
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string { return e.msg }
+
+func usagef(format string, args ...any) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// exitCode maps err to the process exit code.
+func exitCode(err error) int {
+	var usageErr *usageError
+	var fieldErr *validate.FieldError
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &usageErr), errors.Is(err, flag.ErrHelp):
+		return exitUsage
+	case errors.Is(err, ErrPersonNotFound):
+		return exitNotFound
+	case errors.As(err, &fieldErr):
+		return exitInvalid
+	default:
+		return exitFailure
+	}
+}
+
+// run executes the command line args and returns the exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	err := dispatch(args, stdout, stderr)
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		fmt.Fprintln(stderr, err)
+		var usageErr *usageError
+		if errors.As(err, &usageErr) {
+			fmt.Fprint(stderr, usage)
+		}
+	}
+	return exitCode(err)
+}
+
+func dispatch(args []string, stdout, stderr io.Writer) error {
+	global := newFlagSet("structsinterfaces", stderr)
+	file := global.String("file", "people.json", "directory `path`")
+	if err := parseFlags(global, args); err != nil {
+		return err
+	}
+	if global.NArg() == 0 {
+		return usagef("missing command")
+	}
+
+	cmd, args := global.Arg(0), global.Args()[1:]
+	if cmd == "inspect" {
+		return cmdInspect(args, stdout, stderr)
+	}
+
+	// The other commands work on the directory, opened only once the
+	// command is known to need it.
+	dirCommands := map[string]func(dir *Directory) error{
+		"add":    func(dir *Directory) error { return cmdAdd(dir, args, stdout, stderr) },
+		"list":   func(dir *Directory) error { return cmdList(dir, args, stdout, stderr) },
+		"show":   func(dir *Directory) error { return cmdShow(dir, args, stdout, stderr) },
+		"remove": func(dir *Directory) error { return cmdRemove(dir, args, stderr) },
+		"greet":  func(dir *Directory) error { return cmdGreet(dir, args, stdout, stderr) },
+	}
+	dirCmd, ok := dirCommands[cmd]
+	if !ok {
+		return usagef("unknown command %q", cmd)
+	}
+	dir, err := OpenDirectory(*file)
+	if err != nil {
+		return err
+	}
+	return dirCmd(dir)
+}
+
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	return fs
+}
+
+// parseFlags is fs.Parse, except that an invalid flag, which fs has already
+// reported along with its defaults, becomes flag.ErrHelp: run then exits
+// with exitUsage without reporting it a second time.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return flag.ErrHelp
+	}
+	return nil
+}
+
+func cmdAdd(dir *Directory, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("add", stderr)
+	var p Person
+	var birthdate string
+	fs.StringVar(&p.Name, "name", "", "full name")
+	fs.StringVar(&p.Email, "email", "", "email address")
+	fs.StringVar(&p.Phone, "phone", "", "E.164 phone number")
+	fs.StringVar(&birthdate, "birthdate", "", "birthdate as YYYY-MM-DD")
+	fs.BoolVar(&p.IsAdmin, "admin", false, "mark as administrator")
+	format := fs.String("format", "table", "output format: table, json or csv")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usagef("add takes no arguments")
+	}
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+	if birthdate != "" {
+		t, err := time.Parse(time.DateOnly, birthdate)
+		if err != nil {
+			return usagef("invalid -birthdate %q, want YYYY-MM-DD", birthdate)
+		}
+		p.Birthdate = t
+	}
+
+	p, err := dir.Add(p)
+	if err != nil {
+		return err
+	}
+	if err := dir.Save(); err != nil {
+		return err
+	}
+	return writePerson(stdout, *format, p)
+}
+
+func cmdList(dir *Directory, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("list", stderr)
+	format := fs.String("format", "table", "output format: table, json or csv")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usagef("list takes no arguments")
+	}
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+	return writePeople(stdout, *format, dir.Sorted()...)
+}
+
+func cmdShow(dir *Directory, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("show", stderr)
+	format := fs.String("format", "table", "output format: table, json or csv")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+	id, err := parseID(fs)
+	if err != nil {
+		return err
+	}
+	p, err := dir.Get(id)
+	if err != nil {
+		return err
+	}
+	return writePerson(stdout, *format, p)
+}
+
+func cmdRemove(dir *Directory, args []string, stderr io.Writer) error {
+	fs := newFlagSet("remove", stderr)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	id, err := parseID(fs)
+	if err != nil {
+		return err
+	}
+	if err := dir.Remove(id); err != nil {
+		return err
+	}
+	return dir.Save()
+}
+
+func cmdGreet(dir *Directory, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("greet", stderr)
+	style := fs.String("style", DefaultStyle, "greeting style: "+strings.Join(Styles(), ", "))
+	templatePath := fs.String("template", "", "greet with the template file at `path` instead of a style")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	id, err := parseID(fs)
+	if err != nil {
+		return err
+	}
+
+	newGreeter, err := LookupStyle(*style)
+	if err != nil {
+		return &usageError{msg: err.Error()}
+	}
+	if *templatePath != "" {
+		templates := TemplatesFromDir(filepath.Dir(*templatePath))
+		newGreeter = func(p Person) WriterGreeter {
+			return TemplateGreeter{Person: p, Templates: templates, Name: filepath.Base(*templatePath)}
+		}
+	}
+
+	p, err := dir.Get(id)
+	if err != nil {
+		return err
+	}
+	return newGreeter(p).GreetTo(stdout)
+}
+
+// inspectable are the types the inspect command knows about, and
+// capabilities the interfaces it checks them against.
+var (
+	inspectable = map[string]any{
+		"Person":   Person{},
+		"Employee": Employee{},
+		"Customer": Customer{},
+		"Guest":    Guest{},
+		"Badge":    Badge{},
+		"Mime":     Mime{},
+	}
+	capabilities = []reflect.Type{
+		reflect.TypeFor[Greeter](),
+		reflect.TypeFor[WriterGreeter](),
+		reflect.TypeFor[Introducer](),
+		reflect.TypeFor[Farewell](),
+		reflect.TypeFor[Mutator](),
+		reflect.TypeFor[Builder](),
+		reflect.TypeFor[fmt.Stringer](),
+		reflect.TypeFor[encoding.TextMarshaler](),
+	}
+)
+
+func cmdInspect(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("inspect", stderr)
+	format := fs.String("format", "tree", "output format: tree or json")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usagef("inspect takes exactly one type name")
+	}
+	v, ok := inspectable[fs.Arg(0)]
+	if !ok {
+		return usagef("unknown type %q", fs.Arg(0))
+	}
+
+	report := inspect.Of(v, capabilities...)
+	switch *format {
+	case "tree":
+		return report.WriteTree(stdout)
+	case "json":
+		return report.WriteJSON(stdout)
+	}
+	return usagef("unknown format %q, want tree or json", *format)
+}
+
+// parseID reads the single ID argument left in fs.
+func parseID(fs *flag.FlagSet) (uint64, error) {
+	if fs.NArg() != 1 {
+		return 0, usagef("%s takes exactly one ID", fs.Name())
+	}
+	id, err := strconv.ParseUint(fs.Arg(0), 10, 64)
+	if err != nil {
+		return 0, usagef("invalid ID %q", fs.Arg(0))
+	}
+	return id, nil
+}
+
+func checkFormat(format string) error {
+	switch format {
+	case "table", "json", "csv":
+		return nil
+	}
+	return usagef("unknown format %q, want table, json or csv", format)
+}
+
+// writePerson renders a single person: like writePeople, except that JSON
+// is an object instead of an array.
+func writePerson(w io.Writer, format string, p Person) error {
+	if format != "json" {
+		return writePeople(w, format, p)
+	}
+	data, err := Marshal(p, JSON)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// writePeople renders people to w as a table, a JSON array or CSV with a
+// header row.
+func writePeople(w io.Writer, format string, people ...Person) error {
+	switch format {
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tNAME\tEMAIL\tPHONE\tBIRTHDATE\tADMIN")
+		for _, p := range people {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%t\n", p.ID, p.Name, p.Email, p.Phone, formatDate(p.Birthdate), p.IsAdmin)
+		}
+		return tw.Flush()
+	case "json":
+		if people == nil {
+			people = []Person{}
+		}
+		data, err := Marshal(people, JSON)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	case "csv":
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"id", "name", "email", "phone", "birthdate", "is_admin"})
+		for _, p := range people {
+			cw.Write([]string{
+				strconv.FormatUint(p.ID, 10), p.Name, p.Email, p.Phone,
+				formatDate(p.Birthdate), strconv.FormatBool(p.IsAdmin),
+			})
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+	return checkFormat(format)
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.DateOnly)
+}
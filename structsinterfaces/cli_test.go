@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// cli runs the command line against the directory file path and returns
+// the exit code and both outputs.
+func cli(t *testing.T, path string, args ...string) (code int, stdout, stderr string) {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	code = run(append([]string{"-file", path}, args...), &out, &errOut)
+	return code, out.String(), errOut.String()
+}
+
+// mustCLI is cli for command lines that must succeed.
+func mustCLI(t *testing.T, path string, args ...string) string {
+	t.Helper()
+	code, stdout, stderr := cli(t, path, args...)
+	if code != exitOK {
+		t.Fatalf("%q exited %d: %s", args, code, stderr)
+	}
+	return stdout
+}
+
+// testDirectory returns the path of a directory file holding two people.
+func testDirectory(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "people.json")
+	mustCLI(t, path, "add", "-name", "Salmo Mascarenhas", "-email", "salmo@example.com",
+		"-phone", "+5511999999999", "-birthdate", "1990-05-17", "-admin")
+	mustCLI(t, path, "add", "-name", "Ana Lima", "-birthdate", "1985-01-02")
+	return path
+}
+
+func TestCLIFormats(t *testing.T) {
+	path := testDirectory(t)
+	for _, format := range []string{"table", "json", "csv"} {
+		t.Run(format, func(t *testing.T) {
+			checkGolden(t, "cli_list_"+format, []byte(mustCLI(t, path, "list", "-format", format)))
+			checkGolden(t, "cli_show_"+format, []byte(mustCLI(t, path, "show", "-format", format, "1")))
+		})
+	}
+}
+
+func TestCLIAdd(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "people.json")
+	checkGolden(t, "cli_add", []byte(mustCLI(t, path, "add", "-format", "csv", "-name", "Salmo")))
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("add didn't save the directory: %v", err)
+	}
+	// IDs keep counting from the saved directory.
+	if got := mustCLI(t, path, "add", "-format", "json", "-name", "Ana Lima"); !strings.Contains(got, `"id": 2`) {
+		t.Errorf("second add:\n%s\nwant id 2", got)
+	}
+}
+
+func TestCLIListEmpty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "people.json")
+	if got := mustCLI(t, path, "list", "-format", "json"); got != "[]\n" {
+		t.Errorf("list of a missing directory = %q, want []", got)
+	}
+}
+
+func TestCLIRemove(t *testing.T) {
+	path := testDirectory(t)
+	if got := mustCLI(t, path, "remove", "1"); got != "" {
+		t.Errorf("remove wrote %q, want nothing", got)
+	}
+	if code, _, _ := cli(t, path, "show", "1"); code != exitNotFound {
+		t.Errorf("show of a removed ID exited %d, want %d", code, exitNotFound)
+	}
+	if got := mustCLI(t, path, "list", "-format", "csv"); strings.Contains(got, "Salmo") {
+		t.Errorf("Salmo still listed after remove:\n%s", got)
+	}
+}
+
+func TestCLIGreet(t *testing.T) {
+	path := testDirectory(t)
+	if got := mustCLI(t, path, "greet", "1"); got != "Hello, my name is Salmo Mascarenhas\n" {
+		t.Errorf("greet = %q", got)
+	}
+	if got := mustCLI(t, path, "greet", "-style", "formal", "2"); !strings.Contains(got, "Ana Lima") {
+		t.Errorf("formal greet = %q, want it to name Ana Lima", got)
+	}
+
+	tmpl := filepath.Join(t.TempDir(), "hi.tmpl")
+	if err := os.WriteFile(tmpl, []byte("Hi {{.Name | initials}}!\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := mustCLI(t, path, "greet", "-template", tmpl, "1"); got != "Hi SM!\n" {
+		t.Errorf("template greet = %q, want %q", got, "Hi SM!\n")
+	}
+}
+
+func TestCLIInspectIgnoresDirectory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "people.json")
+	if err := os.WriteFile(path, []byte("{corrupt"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := mustCLI(t, path, "inspect", "Person"); !strings.Contains(got, "Greet") {
+		t.Errorf("inspect Person:\n%s", got)
+	}
+	if code, _, stderr := cli(t, path, "list"); code != exitFailure || !strings.Contains(stderr, path) {
+		t.Errorf("list of a corrupt directory exited %d with %q, want %d naming the file", code, stderr, exitFailure)
+	}
+}
+
+func TestCLIExitCodes(t *testing.T) {
+	path := testDirectory(t)
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{nil, exitUsage},
+		{[]string{"frobnicate"}, exitUsage},
+		{[]string{"-nosuchflag", "list"}, exitUsage},
+		{[]string{"list", "-h"}, exitUsage},
+		{[]string{"list", "extra"}, exitUsage},
+		{[]string{"list", "-format", "xml"}, exitUsage},
+		{[]string{"show"}, exitUsage},
+		{[]string{"show", "x"}, exitUsage},
+		{[]string{"greet", "-style", "nope", "1"}, exitUsage},
+		{[]string{"add", "-name", "Salmo", "-birthdate", "17/05/1990"}, exitUsage},
+		{[]string{"inspect", "Nope"}, exitUsage},
+		{[]string{"show", "99"}, exitNotFound},
+		{[]string{"remove", "99"}, exitNotFound},
+		{[]string{"greet", "99"}, exitNotFound},
+		{[]string{"add", "-name", "Al"}, exitInvalid},
+		{[]string{"add", "-name", "Salmo", "-email", "not an email"}, exitInvalid},
+		{[]string{"add", "-name", "Salmo", "-phone", "123"}, exitInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			code, stdout, stderr := cli(t, path, tt.args...)
+			if code != tt.want {
+				t.Errorf("exit code %d, want %d; stderr:\n%s", code, tt.want, stderr)
+			}
+			if stdout != "" {
+				t.Errorf("failing command wrote to stdout: %q", stdout)
+			}
+			if stderr == "" {
+				t.Error("failing command wrote nothing to stderr")
+			}
+		})
+	}
+
+	// Failed commands don't touch the directory.
+	if got := mustCLI(t, path, "list", "-format", "csv"); strings.Count(got, "\n") != 3 {
+		t.Errorf("directory changed by failed commands:\n%s", got)
+	}
+}
+
+func TestCLIUsage(t *testing.T) {
+	_, _, stderr := cli(t, filepath.Join(t.TempDir(), "people.json"), "frobnicate")
+	if !strings.HasPrefix(stderr, "unknown command \"frobnicate\"\nusage: structsinterfaces") {
+		t.Errorf("stderr:\n%s\nwant the error followed by the usage", stderr)
+	}
+
+	_, _, stderr = cli(t, filepath.Join(t.TempDir(), "people.json"), "list", "-bogus")
+	if n := strings.Count(stderr, "flag provided but not defined: -bogus"); n != 1 {
+		t.Errorf("bad flag reported %d times, want once:\n%s", n, stderr)
+	}
+}
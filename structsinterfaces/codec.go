@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is a serialization format supported by Marshal and Unmarshal.
+type Format string
+
+const (
+	JSON Format = "json"
+	YAML Format = "yaml"
+	TOML Format = "toml"
+)
+
+// ParseFormat accepts a format name in any case, plus the "yml" alias.
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "json":
+		return JSON, nil
+	case "yaml", "yml":
+		return YAML, nil
+	case "toml":
+		return TOML, nil
+	}
+	return "", fmt.Errorf("unknown format %q", name)
+}
+
+// Marshal encodes v in format f. The json struct tags name the keys in every
+// format, so a struct only has to be tagged once.
+func Marshal(v any, f Format) ([]byte, error) {
+	if f == JSON {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	}
+
+	fields, err := toPlain(reflect.ValueOf(v))
+	if err != nil {
+		return nil, err
+	}
+	switch f {
+	case YAML:
+		return yaml.Marshal(fields)
+	case TOML:
+		// A TOML document is a table; the encoder panics on nil.
+		if _, ok := fields.(map[string]any); !ok {
+			return nil, fmt.Errorf("toml: cannot encode %T as a document, only structs and maps", v)
+		}
+		if err := checkTOMLIntegers(fields, ""); err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err := toml.NewEncoder(&buf).Encode(fields); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	return nil, fmt.Errorf("unknown format %q", f)
+}
+
+// Unmarshal decodes data in format f into v, which must be a pointer.
+// Decoding is strict: keys that don't match a json tag of v are an error.
+func Unmarshal(data []byte, f Format, v any) error {
+	if f != JSON {
+		// Any document, not only a table: a YAML sequence decodes into a
+		// slice as it does in JSON.
+		var fields any
+		switch f {
+		case YAML:
+			if err := yaml.Unmarshal(data, &fields); err != nil {
+				return err
+			}
+		case TOML:
+			if _, err := toml.Decode(string(data), &fields); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unknown format %q", f)
+		}
+		var err error
+		if data, err = json.Marshal(fields); err != nil {
+			return err
+		}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		if f != JSON {
+			return &DecodeError{Format: f, Err: err}
+		}
+		return err
+	}
+	return nil
+}
+
+// DecodeError is a YAML or TOML document that parsed but doesn't fit v,
+// such as one with an unknown key. Unmarshal checks those through
+// encoding/json, so Err is a json error; DecodeError names the real format
+// instead of "json".
+type DecodeError struct {
+	Format Format
+	Err    error
+}
+
+func (e *DecodeError) Error() string {
+	return string(e.Format) + ": " + strings.TrimPrefix(e.Err.Error(), "json: ")
+}
+
+func (e *DecodeError) Unwrap() error { return e.Err }
+
+// checkTOMLIntegers returns an error for the first unsigned integer in
+// fields, as returned by toPlain, that doesn't fit the int64 of TOML:
+// the encoder would write it, but no decoder could read it back. path
+// names the key of fields for the error.
+func checkTOMLIntegers(fields any, path string) error {
+	switch fields := fields.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(fields))
+		for key := range fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			keyPath := key
+			if path != "" {
+				keyPath = path + "." + key
+			}
+			if err := checkTOMLIntegers(fields[key], keyPath); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for i, item := range fields {
+			if err := checkTOMLIntegers(item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	default:
+		v := reflect.ValueOf(fields)
+		switch v.Kind() {
+		case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+			if v.Uint() > math.MaxInt64 {
+				return fmt.Errorf("toml: %s: %d overflows a TOML integer", path, v.Uint())
+			}
+		}
+	}
+	return nil
+}
+
+// toPlain turns v into maps, slices and scalars keyed by json tag names,
+// which the YAML and TOML encoders handle without tags of their own.
+// time.Time is kept as is so both formats write a native timestamp.
+func toPlain(v reflect.Value) (any, error) {
+	// A nil v, as from reflect.ValueOf(nil), has no value to inspect.
+	if !v.IsValid() {
+		return nil, nil
+	}
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+	if t, ok := v.Interface().(time.Time); ok {
+		return t, nil
+	}
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		text, err := m.MarshalText()
+		return string(text), err
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		fields := make(map[string]any)
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" && opts == "" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			if strings.Contains(opts, "omitempty") && v.Field(i).IsZero() {
+				continue
+			}
+			value, err := toPlain(v.Field(i))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", field.Name, err)
+			}
+			if value != nil {
+				fields[name] = value
+			}
+		}
+		return fields, nil
+	case reflect.Slice, reflect.Array:
+		items := make([]any, v.Len())
+		for i := range items {
+			item, err := toPlain(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return items, nil
+	case reflect.Map:
+		items := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			item, err := toPlain(iter.Value())
+			if err != nil {
+				return nil, err
+			}
+			items[fmt.Sprint(iter.Key().Interface())] = item
+		}
+		return items, nil
+	}
+	return v.Interface(), nil
+}
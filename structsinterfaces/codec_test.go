@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+var formats = []Format{JSON, YAML, TOML}
+
+func testPerson() Person {
+	return Person{
+		ID:        42,
+		Name:      "Salmo Mascarenhas",
+		Birthdate: time.Date(1990, 5, 17, 8, 30, 0, 0, time.UTC),
+		IsAdmin:   true,
+		Email:     "salmo@example.com",
+		Phone:     "+5511999999999",
+	}
+}
+
+func TestCodecRoundTrip(t *testing.T) {
+	for _, f := range formats {
+		t.Run(string(f), func(t *testing.T) {
+			want := testPerson()
+			data, err := Marshal(want, f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got Person
+			if err := Unmarshal(data, f, &got); err != nil {
+				t.Fatalf("Unmarshal: %v\n%s", err, data)
+			}
+			if !got.Birthdate.Equal(want.Birthdate) {
+				t.Errorf("Birthdate = %v, want %v", got.Birthdate, want.Birthdate)
+			}
+			got.Birthdate = want.Birthdate
+			if got != want {
+				t.Errorf("round trip through %s:\ngot  %+v\nwant %+v\n%s", f, got, want, data)
+			}
+		})
+	}
+}
+
+func TestCodecUsesJSONTags(t *testing.T) {
+	for _, f := range formats {
+		data, err := Marshal(testPerson(), f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, key := range []string{"id", "name", "birthdate", "is_admin", "email", "phone"} {
+			if !strings.Contains(string(data), key) {
+				t.Errorf("%s output has no %q key:\n%s", f, key, data)
+			}
+		}
+		if strings.Contains(string(data), "IsAdmin") {
+			t.Errorf("%s output uses the Go field name IsAdmin:\n%s", f, data)
+		}
+	}
+}
+
+func TestUnmarshalStrict(t *testing.T) {
+	docs := map[Format]string{
+		JSON: `{"name": "Salmo", "bogus": 1}`,
+		YAML: "name: Salmo\nbogus: 1\n",
+		TOML: "name = \"Salmo\"\nbogus = 1\n",
+	}
+	for _, f := range formats {
+		t.Run(string(f), func(t *testing.T) {
+			var p Person
+			err := Unmarshal([]byte(docs[f]), f, &p)
+			if err == nil {
+				t.Fatal("unknown field was accepted")
+			}
+			want := string(f) + `: unknown field "bogus"`
+			if err.Error() != want {
+				t.Errorf("error = %q, want %q", err, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalTypeError(t *testing.T) {
+	docs := map[Format]string{
+		JSON: `{"id": "x"}`,
+		YAML: "id: x\n",
+		TOML: "id = \"x\"\n",
+	}
+	for _, f := range formats {
+		t.Run(string(f), func(t *testing.T) {
+			var p Person
+			err := Unmarshal([]byte(docs[f]), f, &p)
+			var typeErr *json.UnmarshalTypeError
+			if !errors.As(err, &typeErr) || typeErr.Field != "id" {
+				t.Fatalf("got %v, want a *json.UnmarshalTypeError for id", err)
+			}
+			if !strings.HasPrefix(err.Error(), string(f)+": ") {
+				t.Errorf("error %q doesn't name the format %s", err, f)
+			}
+		})
+	}
+}
+
+func TestMarshalNil(t *testing.T) {
+	for _, v := range []any{nil, (*Person)(nil)} {
+		for _, f := range []Format{JSON, YAML} {
+			data, err := Marshal(v, f)
+			if err != nil || strings.TrimSpace(string(data)) != "null" {
+				t.Errorf("Marshal(%#v, %s) = %q, %v; want null", v, f, data, err)
+			}
+		}
+		// TOML has no null: a document must be a table.
+		if _, err := Marshal(v, TOML); err == nil {
+			t.Errorf("Marshal(%#v, toml) succeeded, want an error", v)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for name, want := range map[string]Format{"json": JSON, "YAML": YAML, "yml": YAML, "Toml": TOML} {
+		if got, err := ParseFormat(name); got != want || err != nil {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q", name, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("ParseFormat(xml) succeeded")
+	}
+}
+
+func TestCodecRoundTripSlice(t *testing.T) {
+	other := testPerson()
+	other.ID, other.Name, other.IsAdmin = 43, "Ana Lima", false
+	want := []Person{testPerson(), other}
+	// TOML documents are tables, so only JSON and YAML encode a slice.
+	for _, f := range []Format{JSON, YAML} {
+		t.Run(string(f), func(t *testing.T) {
+			data, err := Marshal(want, f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []Person
+			if err := Unmarshal(data, f, &got); err != nil {
+				t.Fatalf("Unmarshal: %v\n%s", err, data)
+			}
+			if !slices.EqualFunc(got, want, Person.Equal) {
+				t.Errorf("round trip through %s:\ngot  %+v\nwant %+v\n%s", f, got, want, data)
+			}
+		})
+	}
+}
+
+func TestMarshalTOMLIntegerRange(t *testing.T) {
+	p := testPerson()
+	p.ID = math.MaxInt64
+	data, err := Marshal(p, TOML)
+	if err != nil {
+		t.Fatalf("ID MaxInt64: %v", err)
+	}
+	var got Person
+	if err := Unmarshal(data, TOML, &got); err != nil || got.ID != p.ID {
+		t.Errorf("ID MaxInt64 read back as %d, %v", got.ID, err)
+	}
+
+	p.ID = math.MaxInt64 + 1
+	if data, err := Marshal(p, TOML); err == nil || !strings.Contains(err.Error(), "id") {
+		t.Errorf("ID MaxInt64+1: got %q, %v; want an error naming id", data, err)
+	}
+	nested := map[string]any{"people": []Person{testPerson(), p}}
+	if _, err := Marshal(nested, TOML); err == nil || !strings.Contains(err.Error(), "people[1].id") {
+		t.Errorf("nested ID MaxInt64+1: got %v, want an error naming people[1].id", err)
+	}
+	// Other formats have no such limit.
+	for _, f := range []Format{JSON, YAML} {
+		data, err := Marshal(p, f)
+		if err != nil {
+			t.Fatalf("%s: %v", f, err)
+		}
+		var got Person
+		if err := Unmarshal(data, f, &got); err != nil || got.ID != p.ID {
+			t.Errorf("%s: ID read back as %d, %v", f, got.ID, err)
+		}
+	}
+}
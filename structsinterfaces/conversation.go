@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// The capabilities a conversation participant may have, besides Greeter,
+// WriterGreeter, fmt.Stringer and encoding.TextMarshaler. Each one is a
+// single method, so types can pick any subset.
+
+// Introducer says a few words about itself after everyone has greeted.
+type Introducer interface {
+	Introduce(w io.Writer) error
+}
+
+// Farewell says goodbye when the conversation ends.
+type Farewell interface {
+	SayGoodbye(w io.Writer) error
+}
+
+func (p Person) String() string {
+	return p.Name
+}
+
+func (p Person) Introduce(w io.Writer) error {
+	return writeMessage(w, "introduction", p.Name)
+}
+
+func (p Person) SayGoodbye(w io.Writer) error {
+	return writeMessage(w, "farewell", p.Name)
+}
+
+// Guest is known by name and says goodbye, but neither greets nor
+// introduces itself.
+type Guest struct {
+	Name string
+}
+
+func (g Guest) String() string {
+	return g.Name
+}
+
+func (g Guest) SayGoodbye(w io.Writer) error {
+	return writeMessage(w, "farewell", g.Name)
+}
+
+// Badge only knows how to render itself as text.
+type Badge struct {
+	ID uint64
+}
+
+func (b Badge) MarshalText() ([]byte, error) {
+	return []byte("#" + strconv.FormatUint(b.ID, 10)), nil
+}
+
+// Mime has no capabilities at all.
+type Mime struct{}
+
+// Conversation runs participants through greetings, introductions and
+// farewells, using whatever capability each one has and falling back to a
+// neutral line for those that lack it.
+type Conversation struct {
+	Participants []any
+}
+
+// Run writes the conversation to w and stops at the first write failure.
+//
+// Greet can't be redirected to w, so a participant that implements Greeter
+// but not WriterGreeter waves like one that can't greet at all.
+func (c Conversation) Run(w io.Writer) error {
+	for i, p := range c.Participants {
+		var err error
+		if g, ok := p.(WriterGreeter); ok {
+			err = g.GreetTo(w)
+		} else {
+			err = writeMessage(w, "conversation.wave", c.name(i))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for _, p := range c.Participants {
+		if in, ok := p.(Introducer); ok {
+			if err := in.Introduce(w); err != nil {
+				return err
+			}
+		}
+	}
+	for i, p := range c.Participants {
+		var err error
+		if f, ok := p.(Farewell); ok {
+			err = f.SayGoodbye(w)
+		} else {
+			err = writeMessage(w, "conversation.leave", c.name(i))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// name finds something to call participant i by: its String, its text
+// form, or its position in the conversation.
+func (c Conversation) name(i int) string {
+	switch p := c.Participants[i].(type) {
+	case fmt.Stringer:
+		return p.String()
+	case encoding.TextMarshaler:
+		if text, err := p.MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+	return localizer.Translate("conversation.participant", Args{Count: i + 1})
+}
+
+// Capabilities lists the capability interfaces v implements, for
+// diagnostics.
+func Capabilities(v any) []string {
+	var caps []string
+	if _, ok := v.(Greeter); ok {
+		caps = append(caps, "Greeter")
+	}
+	if _, ok := v.(WriterGreeter); ok {
+		caps = append(caps, "WriterGreeter")
+	}
+	if _, ok := v.(Introducer); ok {
+		caps = append(caps, "Introducer")
+	}
+	if _, ok := v.(Farewell); ok {
+		caps = append(caps, "Farewell")
+	}
+	if _, ok := v.(fmt.Stringer); ok {
+		caps = append(caps, "fmt.Stringer")
+	}
+	if _, ok := v.(encoding.TextMarshaler); ok {
+		caps = append(caps, "encoding.TextMarshaler")
+	}
+	return caps
+}
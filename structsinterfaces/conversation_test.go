@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// legacyGreeter only has the stdout Greet, so Run can't use it.
+type legacyGreeter struct {
+	greeted *bool
+}
+
+func (g legacyGreeter) Greet() { *g.greeted = true }
+
+// speaker introduces itself and has nothing else.
+type speaker struct{}
+
+func (speaker) Introduce(w io.Writer) error {
+	_, err := io.WriteString(w, "a speaker says a few words\n")
+	return err
+}
+
+func TestCapabilities(t *testing.T) {
+	p := Person{Name: "Salmo"}
+	tests := []struct {
+		name string
+		v    any
+		want []string
+	}{
+		{"Person", p, []string{"Greeter", "WriterGreeter", "Introducer", "Farewell", "fmt.Stringer"}},
+		{"Employee", Employee{Person: p}, []string{"Greeter", "WriterGreeter", "Introducer", "Farewell", "fmt.Stringer"}},
+		{"AsGreeter", AsGreeter(FormalGreeter{Person: p}), []string{"Greeter", "WriterGreeter"}},
+		{"FormalGreeter", FormalGreeter{Person: p}, []string{"WriterGreeter"}},
+		{"legacyGreeter", legacyGreeter{}, []string{"Greeter"}},
+		{"speaker", speaker{}, []string{"Introducer"}},
+		{"Guest", Guest{Name: "Ana"}, []string{"Farewell", "fmt.Stringer"}},
+		{"Badge", Badge{ID: 7}, []string{"encoding.TextMarshaler"}},
+		{"Mime", Mime{}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Capabilities(tt.v); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Capabilities = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConversationRun(t *testing.T) {
+	p := Person{Name: "Salmo"}
+	var greeted bool
+	c := Conversation{Participants: []any{
+		p, // every capability
+		Employee{Person: p, Role: "engineer", Department: "platform"},
+		AsGreeter(CasualGreeter{Person: p}), // WriterGreeter behind Greeter
+		FormalGreeter{Person: p},            // WriterGreeter only
+		legacyGreeter{greeted: &greeted},    // Greeter only
+		speaker{},                           // Introducer only
+		Guest{Name: "Ana"},                  // Stringer and Farewell
+		Badge{ID: 7},                        // TextMarshaler only
+		Mime{},                              // nothing
+	}}
+	var buf bytes.Buffer
+	if err := c.Run(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if greeted {
+		t.Error("Run called Greet, which writes to stdout instead of w")
+	}
+	checkGolden(t, "conversation", buf.Bytes())
+}
+
+func TestConversationRunWriteError(t *testing.T) {
+	c := Conversation{Participants: []any{Person{Name: "Salmo"}, Mime{}}}
+	// Run writes 2 greetings, 1 introduction and 2 farewells; fail each one.
+	for ok := range 5 {
+		w := &failingWriter{ok: ok}
+		if err := c.Run(w); !errors.Is(err, errDiskFull) {
+			t.Errorf("failing after %d writes: got %v, want %v", ok, err, errDiskFull)
+		}
+	}
+	if err := c.Run(&failingWriter{ok: 5}); err != nil {
+		t.Errorf("5 writes succeeded but Run failed: %v", err)
+	}
+}
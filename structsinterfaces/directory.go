@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// ErrPersonNotFound is returned for an ID that isn't in the directory.
+var ErrPersonNotFound = errors.New("person not found")
+
+// Directory is a list of people persisted as a JSON file.
+type Directory struct {
+	path string
+
+	People []Person `json:"people"`
+	NextID uint64   `json:"next_id"`
+}
+
+// OpenDirectory loads the directory stored at path. A missing file is an
+// empty directory, created on the first Save.
+func OpenDirectory(path string) (*Directory, error) {
+	d := &Directory{path: path, NextID: 1}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return d, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := Unmarshal(data, JSON, d); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return d, nil
+}
+
+// Save writes the directory back to its file. It writes a temporary file
+// first and renames it, so a failed save leaves the old file intact.
+func (d *Directory) Save() error {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(d.path), filepath.Base(d.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), d.path)
+}
+
+// Add validates p, gives it the next free ID and stores it.
+func (d *Directory) Add(p Person) (Person, error) {
+	if err := p.Validate(); err != nil {
+		return Person{}, err
+	}
+	p.ID = d.NextID
+	d.NextID++
+	d.People = append(d.People, p)
+	return p, nil
+}
+
+// Get returns the person with id, or ErrPersonNotFound.
+func (d *Directory) Get(id uint64) (Person, error) {
+	i := d.index(id)
+	if i < 0 {
+		return Person{}, fmt.Errorf("id %d: %w", id, ErrPersonNotFound)
+	}
+	return d.People[i], nil
+}
+
+// Remove deletes the person with id, or returns ErrPersonNotFound.
+func (d *Directory) Remove(id uint64) error {
+	i := d.index(id)
+	if i < 0 {
+		return fmt.Errorf("id %d: %w", id, ErrPersonNotFound)
+	}
+	d.People = slices.Delete(d.People, i, i+1)
+	return nil
+}
+
+// Sorted returns everyone in the directory in Person.Compare order.
+func (d *Directory) Sorted() []Person {
+	return NewPersonList(d.People...).People()
+}
+
+func (d *Directory) index(id uint64) int {
+	return slices.IndexFunc(d.People, func(p Person) bool { return p.ID == id })
+}
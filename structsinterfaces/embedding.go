@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// -> Embedding
+//
+// Go has no inheritance, but a struct can embed another type by listing it
+// without a field name. The fields and methods of the embedded type are
+// promoted: e.Name and e.Introduce(w) work on an Employee as if they were
+// declared on it. Declaring a field or method with the same name in the
+// outer struct shadows the promoted one, which stays reachable through the
+// embedded field, e.g. e.Person.Email.
+//
+// Embedding by value or by pointer changes the method sets:
+//   - Employee embeds Person. Employee gets Person's value receiver methods,
+//     but only *Employee gets the pointer receiver ones (Rename, SetEmail...).
+//   - Customer embeds *Person. Customer and *Customer both get every Person
+//     method, and copies of a Customer share the same Person.
+
+// Employee is a Person at work. Email is the work address; it shadows the
+// personal one in Person.Email.
+type Employee struct {
+	Person
+	Email      string `json:"work_email"`
+	Role       string `json:"role"`
+	Department string `json:"department"`
+}
+
+// Greet and GreetTo shadow Person's: the promoted Person.Greet would call
+// Person.GreetTo and never mention the job.
+func (e Employee) Greet() {
+	_ = e.GreetTo(os.Stdout)
+}
+
+func (e Employee) GreetTo(w io.Writer) error {
+	if err := e.Person.GreetTo(w); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w, localizer.Translate("employee.role", Args{Vars: map[string]string{
+		"role":       e.Role,
+		"department": e.Department,
+	}}))
+	return err
+}
+
+// Customer is a Person who buys from us. It keeps Person's greeting.
+//
+// Person's methods are promoted through the pointer, so a Customer without
+// a Person, such as Customer{}, panics on them. Its own Greet and GreetTo
+// guard against that; NewCustomer always sets a Person.
+type Customer struct {
+	*Person
+	Tier string `json:"tier"`
+}
+
+// ErrNoPerson is returned by Customer.GreetTo when the Customer has no
+// Person to greet as.
+var ErrNoPerson = errors.New("customer has no person")
+
+func NewCustomer(p Person, tier string) Customer {
+	return Customer{Person: &p, Tier: tier}
+}
+
+// Greet and GreetTo shadow the promoted ones, which would dereference a nil
+// Person.
+func (c Customer) Greet() {
+	_ = c.GreetTo(os.Stdout)
+}
+
+func (c Customer) GreetTo(w io.Writer) error {
+	if c.Person == nil {
+		return ErrNoPerson
+	}
+	return c.Person.GreetTo(w)
+}
+
+var (
+	_ Mutator = (*Employee)(nil)
+	_ Mutator = Customer{}
+	_ Mutator = (*Customer)(nil)
+	// Employee{} is not a Mutator: Rename has a pointer receiver and
+	// Employee embeds Person by value.
+
+	_ WriterGreeter = Employee{}
+	_ WriterGreeter = Customer{}
+)
+
+// Roster is a mixed list of greeters: Person, Employee, Customer or any
+// other type with a GreetTo method.
+type Roster []WriterGreeter
+
+// Greet asks everyone on the roster to greet on stdout, in order.
+func (r Roster) Greet() {
+	_ = r.GreetTo(os.Stdout)
+}
+
+// GreetTo writes everyone's greeting to w, in order, stopping at the first
+// failure.
+func (r Roster) GreetTo(w io.Writer) error {
+	return GreetAll(w, r...)
+}
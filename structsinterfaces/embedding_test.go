@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestEmbeddingMethodSets(t *testing.T) {
+	mutator := reflect.TypeFor[Mutator]()
+	tests := []struct {
+		typ  reflect.Type
+		want bool
+	}{
+		// Person by value: only the pointer gets Rename and SetEmail.
+		{reflect.TypeFor[Employee](), false},
+		{reflect.TypeFor[*Employee](), true},
+		// *Person: the value already holds a pointer, so both get them.
+		{reflect.TypeFor[Customer](), true},
+		{reflect.TypeFor[*Customer](), true},
+	}
+	for _, tt := range tests {
+		if got := tt.typ.Implements(mutator); got != tt.want {
+			t.Errorf("%v implements Mutator = %v, want %v", tt.typ, got, tt.want)
+		}
+	}
+
+	// The value method set of Customer includes Rename; Employee's doesn't.
+	if _, ok := reflect.TypeFor[Customer]().MethodByName("Rename"); !ok {
+		t.Error("Customer has no Rename method")
+	}
+	if _, ok := reflect.TypeFor[Employee]().MethodByName("Rename"); ok {
+		t.Error("Employee has a Rename method, want it only on *Employee")
+	}
+}
+
+func TestEmbeddingCopies(t *testing.T) {
+	e := Employee{Person: Person{Name: "Salmo"}}
+	e2 := e
+	e2.Rename("Ana")
+	if e.Name != "Salmo" {
+		t.Errorf("renaming a copy of an Employee renamed the original to %q", e.Name)
+	}
+
+	c := NewCustomer(Person{Name: "Salmo"}, "gold")
+	c2 := c
+	c2.Rename("Ana")
+	if c.Name != "Ana" {
+		t.Errorf("copies of a Customer should share the Person, got %q", c.Name)
+	}
+}
+
+func TestEmbeddingShadowing(t *testing.T) {
+	e := Employee{Person: Person{Name: "Salmo", Email: "salmo@home.example"}, Email: "salmo@work.example"}
+	if e.Email != "salmo@work.example" || e.Person.Email != "salmo@home.example" {
+		t.Errorf("Email = %q, Person.Email = %q", e.Email, e.Person.Email)
+	}
+}
+
+func TestRosterGreetTo(t *testing.T) {
+	p := Person{Name: "Salmo"}
+	r := Roster{
+		p,
+		Employee{Person: p, Role: "engineer", Department: "platform"},
+		NewCustomer(p, "gold"),
+	}
+	var buf bytes.Buffer
+	if err := r.GreetTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "roster", buf.Bytes())
+}
+
+func TestCustomerWithoutPerson(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (Customer{}).GreetTo(&buf); !errors.Is(err, ErrNoPerson) {
+		t.Errorf("Customer{}.GreetTo: got %v, want %v", err, ErrNoPerson)
+	}
+	err := Roster{Person{Name: "Salmo"}, Customer{}}.GreetTo(&buf)
+	if !errors.Is(err, ErrNoPerson) {
+		t.Errorf("Roster with Customer{}: got %v, want %v", err, ErrNoPerson)
+	}
+	if got := buf.String(); got != "Hello, my name is Salmo\n" {
+		t.Errorf("Roster wrote %q before failing", got)
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"cmp"
+	"encoding/binary"
+	"hash/fnv"
+	"slices"
+	"strings"
+)
+
+// Equal reports whether p and q describe the same person. Unlike ==, it
+// compares birthdates as instants, so the same moment in two time zones is
+// equal.
+func (p Person) Equal(q Person) bool {
+	return p.Compare(q) == 0
+}
+
+// Compare orders people by name, then birthdate, breaking ties with the
+// remaining fields so that Compare returns 0 only for equal people. It can
+// be passed to slices.SortFunc as Person.Compare.
+func (p Person) Compare(q Person) int {
+	return cmp.Or(
+		strings.Compare(p.Name, q.Name),
+		p.Birthdate.Compare(q.Birthdate),
+		cmp.Compare(p.ID, q.ID),
+		strings.Compare(p.Email, q.Email),
+		strings.Compare(p.Phone, q.Phone),
+		compareBool(p.IsAdmin, q.IsAdmin),
+	)
+}
+
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// Hash returns an FNV-1a hash of p that is stable across runs and
+// consistent with Equal: equal people always hash the same.
+func (p Person) Hash() uint64 {
+	h := fnv.New64a()
+	var buf [8]byte
+	writeUint := func(n uint64) {
+		binary.BigEndian.PutUint64(buf[:], n)
+		h.Write(buf[:])
+	}
+	writeString := func(s string) {
+		writeUint(uint64(len(s)))
+		h.Write([]byte(s))
+	}
+
+	writeUint(p.ID)
+	writeString(p.Name)
+	birthdate := p.Birthdate.UTC()
+	writeUint(uint64(birthdate.Unix()))
+	writeUint(uint64(birthdate.Nanosecond()))
+	if p.IsAdmin {
+		writeUint(1)
+	} else {
+		writeUint(0)
+	}
+	writeString(p.Email)
+	writeString(p.Phone)
+	return h.Sum64()
+}
+
+// PersonSet holds distinct people according to Equal. People are bucketed
+// by Hash, so the hash is what the underlying map is keyed by.
+type PersonSet struct {
+	buckets map[uint64][]Person
+	size    int
+}
+
+func NewPersonSet(people ...Person) *PersonSet {
+	s := &PersonSet{buckets: make(map[uint64][]Person)}
+	for _, p := range people {
+		s.Add(p)
+	}
+	return s
+}
+
+// Add inserts p and reports whether it wasn't in the set yet.
+func (s *PersonSet) Add(p Person) bool {
+	if s.buckets == nil {
+		s.buckets = make(map[uint64][]Person)
+	}
+	h := p.Hash()
+	if slices.ContainsFunc(s.buckets[h], p.Equal) {
+		return false
+	}
+	s.buckets[h] = append(s.buckets[h], p)
+	s.size++
+	return true
+}
+
+// Remove deletes p and reports whether it was in the set.
+func (s *PersonSet) Remove(p Person) bool {
+	h := p.Hash()
+	i := slices.IndexFunc(s.buckets[h], p.Equal)
+	if i < 0 {
+		return false
+	}
+	s.buckets[h] = slices.Delete(s.buckets[h], i, i+1)
+	if len(s.buckets[h]) == 0 {
+		delete(s.buckets, h)
+	}
+	s.size--
+	return true
+}
+
+func (s *PersonSet) Contains(p Person) bool {
+	return slices.ContainsFunc(s.buckets[p.Hash()], p.Equal)
+}
+
+func (s *PersonSet) Len() int { return s.size }
+
+// People returns the members of s in Compare order.
+func (s *PersonSet) People() []Person {
+	people := make([]Person, 0, s.size)
+	for _, bucket := range s.buckets {
+		people = append(people, bucket...)
+	}
+	slices.SortFunc(people, Person.Compare)
+	return people
+}
+
+// PersonList is a list of people kept sorted by Compare. Duplicates are
+// allowed; use PersonSet for distinct people.
+type PersonList struct {
+	people []Person
+}
+
+func NewPersonList(people ...Person) *PersonList {
+	l := &PersonList{people: slices.Clone(people)}
+	slices.SortStableFunc(l.people, Person.Compare)
+	return l
+}
+
+// Insert adds p after any people equal to it and returns its index.
+func (l *PersonList) Insert(p Person) int {
+	i, _ := slices.BinarySearchFunc(l.people, p, func(e, target Person) int {
+		if c := e.Compare(target); c != 0 {
+			return c
+		}
+		return -1
+	})
+	l.people = slices.Insert(l.people, i, p)
+	return i
+}
+
+// Index returns the position of the first person equal to p, or -1.
+func (l *PersonList) Index(p Person) int {
+	i, found := slices.BinarySearchFunc(l.people, p, Person.Compare)
+	if !found {
+		return -1
+	}
+	return i
+}
+
+// Remove deletes the first person equal to p and reports whether there was
+// one.
+func (l *PersonList) Remove(p Person) bool {
+	i := l.Index(p)
+	if i < 0 {
+		return false
+	}
+	l.people = slices.Delete(l.people, i, i+1)
+	return true
+}
+
+func (l *PersonList) At(i int) Person { return l.people[i] }
+
+func (l *PersonList) Len() int { return len(l.people) }
+
+// People returns a copy of the list, in order.
+func (l *PersonList) People() []Person {
+	return slices.Clone(l.people)
+}
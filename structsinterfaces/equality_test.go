@@ -0,0 +1,169 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+var (
+	saoPaulo = time.FixedZone("BRT", -3*60*60)
+	tokyo    = time.FixedZone("JST", 9*60*60)
+)
+
+func TestHashIsStable(t *testing.T) {
+	p := Person{
+		ID:        1,
+		Name:      "Salmo Mascarenhas",
+		Email:     "salmo@example.com",
+		Phone:     "+5511999999999",
+		Birthdate: time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC),
+		IsAdmin:   true,
+	}
+	// Pinned: a change here breaks every hash stored or sent elsewhere.
+	const want = 0x5621784a9f1f85ce
+	if got := p.Hash(); got != want {
+		t.Errorf("Hash = %#x, want %#x", got, uint64(want))
+	}
+}
+
+func TestEqualAcrossTimeZones(t *testing.T) {
+	utc := Person{Name: "Ana", Birthdate: time.Date(1985, 1, 2, 12, 0, 0, 0, time.UTC)}
+	for _, loc := range []*time.Location{saoPaulo, tokyo, time.Local} {
+		local := utc.WithBirthdate(utc.Birthdate.In(loc))
+		if !local.Equal(utc) || local.Compare(utc) != 0 {
+			t.Errorf("%s: %v isn't Equal to %v", loc, local.Birthdate, utc.Birthdate)
+		}
+		if local.Hash() != utc.Hash() {
+			t.Errorf("%s: equal people hash differently", loc)
+		}
+	}
+
+	later := utc.WithBirthdate(utc.Birthdate.Add(time.Nanosecond))
+	if later.Equal(utc) || later.Hash() == utc.Hash() {
+		t.Error("a nanosecond apart: Equal or same Hash")
+	}
+}
+
+func TestEqualComparesEveryField(t *testing.T) {
+	p := Person{ID: 1, Name: "Ana", Email: "ana@example.com", Phone: "+5511999999999",
+		Birthdate: time.Date(1985, 1, 2, 0, 0, 0, 0, time.UTC)}
+	changes := map[string]func(*Person){
+		"ID":        func(p *Person) { p.ID++ },
+		"Name":      func(p *Person) { p.Name += "!" },
+		"Email":     func(p *Person) { p.Email = "other@example.com" },
+		"Phone":     func(p *Person) { p.Phone = "" },
+		"Birthdate": func(p *Person) { p.Birthdate = p.Birthdate.AddDate(0, 0, 1) },
+		"IsAdmin":   func(p *Person) { p.IsAdmin = true },
+	}
+	for field, change := range changes {
+		q := p
+		change(&q)
+		if p.Equal(q) || p.Compare(q) == 0 || p.Compare(q) != -q.Compare(p) {
+			t.Errorf("%s differs: Equal %t, Compare %d and %d", field, p.Equal(q), p.Compare(q), q.Compare(p))
+		}
+	}
+}
+
+func TestCompareOrder(t *testing.T) {
+	date := func(year int) time.Time { return time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC) }
+	want := []Person{
+		{ID: 9, Name: "Ana", Birthdate: date(1980)},
+		{ID: 1, Name: "Ana", Birthdate: date(1990)},
+		{ID: 2, Name: "Ana", Birthdate: date(1990)},
+		{ID: 3, Name: "Bia"},
+		{ID: 0, Name: "Bia", Birthdate: date(1970)},
+	}
+	got := []Person{want[3], want[2], want[4], want[0], want[1]}
+	slices.SortFunc(got, Person.Compare)
+	if !slices.EqualFunc(got, want, Person.Equal) {
+		t.Errorf("sorted by Compare:\n%+v\nwant name, then birthdate:\n%+v", got, want)
+	}
+}
+
+func TestPersonSet(t *testing.T) {
+	ana := Person{Name: "Ana", Birthdate: time.Date(1985, 1, 2, 0, 0, 0, 0, time.UTC)}
+	bia := Person{Name: "Bia"}
+	s := NewPersonSet(ana, bia, ana)
+	if s.Len() != 2 {
+		t.Fatalf("Len = %d, want 2", s.Len())
+	}
+	if s.Add(ana.WithBirthdate(ana.Birthdate.In(tokyo))) {
+		t.Error("Add of ana in another time zone reported a new member")
+	}
+	if !s.Contains(ana) || !s.Contains(bia) || s.Contains(Person{Name: "Caio"}) {
+		t.Error("Contains disagrees with the members added")
+	}
+
+	if !s.Remove(ana.WithBirthdate(ana.Birthdate.In(saoPaulo))) {
+		t.Error("Remove of ana in another time zone found nothing")
+	}
+	if s.Remove(ana) {
+		t.Error("Remove of ana succeeded twice")
+	}
+	if s.Contains(ana) || s.Len() != 1 {
+		t.Errorf("after Remove: Contains(ana) %t, Len %d", s.Contains(ana), s.Len())
+	}
+	if got := s.People(); !slices.EqualFunc(got, []Person{bia}, Person.Equal) {
+		t.Errorf("People = %+v, want only Bia", got)
+	}
+
+	var zero PersonSet
+	if !zero.Add(bia) || !zero.Contains(bia) || zero.Len() != 1 {
+		t.Error("the zero PersonSet isn't usable")
+	}
+}
+
+func TestPersonList(t *testing.T) {
+	birthdate := time.Date(1985, 1, 2, 0, 0, 0, 0, time.UTC)
+	ana := Person{Name: "Ana", Birthdate: birthdate}
+	bia := Person{Name: "Bia"}
+	l := NewPersonList(bia, ana)
+	if l.Len() != 2 || !l.At(0).Equal(ana) || !l.At(1).Equal(bia) {
+		t.Fatalf("NewPersonList didn't sort: %+v", l.People())
+	}
+
+	// The same person in two time zones: equal, but each keeps its zone, so
+	// the order they end up in shows where Insert put them.
+	anaSP := ana.WithBirthdate(birthdate.In(saoPaulo))
+	anaJP := ana.WithBirthdate(birthdate.In(tokyo))
+	if i := l.Insert(anaSP); i != 1 {
+		t.Errorf("Insert(ana in BRT) = %d, want 1, after the equal ana", i)
+	}
+	if i := l.Insert(anaJP); i != 2 {
+		t.Errorf("Insert(ana in JST) = %d, want 2, after both equal people", i)
+	}
+	zones := []*time.Location{time.UTC, saoPaulo, tokyo}
+	for i, loc := range zones {
+		if got := l.At(i).Birthdate.Location(); got != loc {
+			t.Errorf("At(%d) is in %s, want %s", i, got, loc)
+		}
+	}
+	if i := l.Insert(Person{Name: "Aaron"}); i != 0 {
+		t.Errorf("Insert(Aaron) = %d, want 0", i)
+	}
+
+	if i := l.Index(anaJP); i != 1 {
+		t.Errorf("Index(ana) = %d, want 1, the first equal person", i)
+	}
+	if i := l.Index(Person{Name: "Caio"}); i != -1 {
+		t.Errorf("Index(Caio) = %d, want -1", i)
+	}
+
+	for range 3 {
+		if !l.Remove(ana) {
+			t.Fatal("Remove(ana) found nothing")
+		}
+	}
+	if l.Remove(ana) {
+		t.Error("Remove(ana) succeeded a fourth time")
+	}
+	got := l.People()
+	if want := []Person{{Name: "Aaron"}, bia}; !slices.EqualFunc(got, want, Person.Equal) {
+		t.Errorf("People = %+v, want %+v", got, want)
+	}
+	got[0].Name = "changed"
+	if l.At(0).Name != "Aaron" {
+		t.Error("People doesn't return a copy")
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// AsGreeter adapts a WriterGreeter to the original Greeter interface, so
+// callers that only know Greet() keep printing to stdout.
+func AsGreeter(g WriterGreeter) Greeter {
+	return stdoutGreeter{g}
+}
+
+type stdoutGreeter struct {
+	WriterGreeter
+}
+
+func (s stdoutGreeter) Greet() {
+	_ = s.GreetTo(os.Stdout)
+}
+
+// GreetString renders g's greeting into a string.
+func GreetString(g WriterGreeter) (string, error) {
+	var b strings.Builder
+	if err := g.GreetTo(&b); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// GreetAll writes every greeting to w, stopping at the first failure.
+func GreetAll(w io.Writer, greeters ...WriterGreeter) error {
+	for i, g := range greeters {
+		if err := g.GreetTo(w); err != nil {
+			return fmt.Errorf("greeter %d: %w", i, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// TestMain pins the locale: localizer otherwise follows LANG, and the golden
+// files are in English.
+func TestMain(m *testing.M) {
+	localizer = MustLoadCatalog(EmbeddedLocales()).Localizer("en")
+	os.Exit(m.Run())
+}
+
+// withLocale switches localizer to locale for the rest of the test.
+func withLocale(t *testing.T, locale string) {
+	t.Helper()
+	saved := localizer
+	localizer = MustLoadCatalog(EmbeddedLocales()).Localizer(locale)
+	t.Cleanup(func() { localizer = saved })
+}
+
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s:\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+func TestGreetTo(t *testing.T) {
+	for _, locale := range []string{"en", "pt-BR", "es"} {
+		t.Run(locale, func(t *testing.T) {
+			withLocale(t, locale)
+			var buf bytes.Buffer
+			if err := (Person{Name: "Salmo"}).GreetTo(&buf); err != nil {
+				t.Fatal(err)
+			}
+			checkGolden(t, "greet_to_"+locale, buf.Bytes())
+		})
+	}
+}
+
+func TestGreetAll(t *testing.T) {
+	p := Person{Name: "Salmo"}
+	afternoon := func() time.Time { return time.Date(2024, 6, 1, 15, 0, 0, 0, time.UTC) }
+	var buf bytes.Buffer
+	err := GreetAll(&buf,
+		p,
+		FormalGreeter{Person: p},
+		CasualGreeter{Person: p},
+		RobotGreeter{Person: p},
+		TimeOfDayGreeter{Person: p, Now: afternoon},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "greet_all", buf.Bytes())
+}
+
+func TestGreetString(t *testing.T) {
+	got, err := GreetString(Person{Name: "Salmo"})
+	if want := "Hello, my name is Salmo\n"; got != want || err != nil {
+		t.Errorf("GreetString = %q, %v; want %q, nil", got, err, want)
+	}
+}
+
+var errDiskFull = errors.New("disk full")
+
+// failingWriter accepts ok writes, then fails every later one.
+type failingWriter struct {
+	buf bytes.Buffer
+	ok  int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.ok == 0 {
+		return 0, errDiskFull
+	}
+	w.ok--
+	return w.buf.Write(p)
+}
+
+func TestGreetToWriteError(t *testing.T) {
+	err := Person{Name: "Salmo"}.GreetTo(&failingWriter{})
+	if !errors.Is(err, errDiskFull) {
+		t.Errorf("GreetTo: got %v, want %v", err, errDiskFull)
+	}
+}
+
+func TestGreetAllWriteError(t *testing.T) {
+	w := &failingWriter{ok: 1}
+	p := Person{Name: "Salmo"}
+	err := GreetAll(w, p, FormalGreeter{Person: p}, CasualGreeter{Person: p})
+	if !errors.Is(err, errDiskFull) {
+		t.Fatalf("GreetAll: got %v, want %v", err, errDiskFull)
+	}
+	if want := "greeter 1: disk full"; err.Error() != want {
+		t.Errorf("GreetAll error = %q, want %q", err, want)
+	}
+	if got, want := w.buf.String(), "Hello, my name is Salmo\n"; got != want {
+		t.Errorf("GreetAll wrote %q before failing, want %q", got, want)
+	}
+}
+
+// Compile-time checks that the greeters satisfy WriterGreeter, and that
+// AsGreeter turns one back into a Greeter.
+var (
+	_ WriterGreeter = Person{}
+	_ WriterGreeter = FormalGreeter{}
+	_ WriterGreeter = TimeOfDayGreeter{}
+	_ Greeter       = AsGreeter(Person{})
+)
@@ -0,0 +1,279 @@
+// Package inspect reports what reflection knows about a value: its fields,
+// their types and tags, the method sets of the type and of a pointer to it,
+// and which interfaces each of them satisfies.
+//
+//	r := inspect.Of(Person{}, reflect.TypeFor[fmt.Stringer]())
+//	r.WriteTree(os.Stdout)
+package inspect
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Report describes one type.
+type Report struct {
+	Type string `json:"type"`
+	Kind string `json:"kind"`
+	// Fields is empty for non-struct types.
+	Fields []Field `json:"fields,omitempty"`
+	// ValueMethods is the method set of T, PointerMethods the one of *T.
+	ValueMethods   []Method         `json:"value_methods"`
+	PointerMethods []Method         `json:"pointer_methods"`
+	Implements     []Implementation `json:"implements,omitempty"`
+}
+
+// Field is a struct field. Embedded struct fields list the fields they
+// promote in Fields.
+type Field struct {
+	Name     string            `json:"name"`
+	Type     string            `json:"type"`
+	Exported bool              `json:"exported"`
+	Embedded bool              `json:"embedded,omitempty"`
+	Tags     map[string]string `json:"tags,omitempty"`
+	Fields   []Field           `json:"fields,omitempty"`
+}
+
+// Method is a method with its signature, receiver excluded.
+type Method struct {
+	Name      string `json:"name"`
+	Signature string `json:"signature"`
+}
+
+// Implementation tells whether T and *T satisfy an interface.
+type Implementation struct {
+	Interface string `json:"interface"`
+	Value     bool   `json:"value"`
+	Pointer   bool   `json:"pointer"`
+}
+
+// Of inspects the type of v, checking it against ifaces, which must be
+// interface types (see reflect.TypeFor). If v is a pointer, the type it
+// points to is inspected. Of panics if v is nil or an element of ifaces is
+// not an interface.
+func Of(v any, ifaces ...reflect.Type) Report {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		panic("inspect: Of(nil)")
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return Type(t, ifaces...)
+}
+
+// Type is like Of but takes the type directly.
+func Type(t reflect.Type, ifaces ...reflect.Type) Report {
+	pt := reflect.PointerTo(t)
+	r := Report{
+		Type:           t.String(),
+		Kind:           t.Kind().String(),
+		ValueMethods:   methods(t),
+		PointerMethods: methods(pt),
+	}
+	if t.Kind() == reflect.Struct {
+		r.Fields = fields(t)
+	}
+	for _, iface := range ifaces {
+		if iface.Kind() != reflect.Interface {
+			panic("inspect: " + iface.String() + " is not an interface")
+		}
+		r.Implements = append(r.Implements, Implementation{
+			Interface: iface.String(),
+			Value:     t.Implements(iface),
+			Pointer:   pt.Implements(iface),
+		})
+	}
+	return r
+}
+
+func fields(t reflect.Type) []Field {
+	fs := make([]Field, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		f := Field{
+			Name:     sf.Name,
+			Type:     sf.Type.String(),
+			Exported: sf.IsExported(),
+			Embedded: sf.Anonymous,
+			Tags:     ParseTag(sf.Tag),
+		}
+		if sf.Anonymous {
+			et := sf.Type
+			if et.Kind() == reflect.Pointer {
+				et = et.Elem()
+			}
+			if et.Kind() == reflect.Struct {
+				f.Fields = fields(et)
+			}
+		}
+		fs = append(fs, f)
+	}
+	return fs
+}
+
+// methods lists the exported method set of t, sorted by name.
+func methods(t reflect.Type) []Method {
+	// Method.Type of a concrete type has the receiver as its first
+	// parameter; that of an interface type has no receiver.
+	skip := 1
+	if t.Kind() == reflect.Interface {
+		skip = 0
+	}
+	ms := make([]Method, 0, t.NumMethod())
+	for i := 0; i < t.NumMethod(); i++ {
+		m := t.Method(i)
+		ms = append(ms, Method{Name: m.Name, Signature: signature(m.Type, skip)})
+	}
+	return ms
+}
+
+// signature formats a func type as "(in) out", skipping the first skip
+// parameters (the receiver, if ft has one).
+func signature(ft reflect.Type, skip int) string {
+	var in []string
+	for i := skip; i < ft.NumIn(); i++ {
+		p := ft.In(i).String()
+		if ft.IsVariadic() && i == ft.NumIn()-1 {
+			p = "..." + ft.In(i).Elem().String()
+		}
+		in = append(in, p)
+	}
+	var out []string
+	for i := 0; i < ft.NumOut(); i++ {
+		out = append(out, ft.Out(i).String())
+	}
+
+	s := "(" + strings.Join(in, ", ") + ")"
+	switch len(out) {
+	case 0:
+	case 1:
+		s += " " + out[0]
+	default:
+		s += " (" + strings.Join(out, ", ") + ")"
+	}
+	return s
+}
+
+// ParseTag splits a struct tag into its key/value pairs, following the
+// conventional `key:"value" key2:"value2"` format understood by
+// reflect.StructTag.Get. It stops at the first malformed pair.
+func ParseTag(tag reflect.StructTag) map[string]string {
+	var kv map[string]string
+	s := string(tag)
+	for {
+		s = strings.TrimLeft(s, " ")
+		colon := strings.Index(s, `:"`)
+		if colon <= 0 || strings.ContainsAny(s[:colon], " \"") {
+			return kv
+		}
+		key := s[:colon]
+		s = s[colon+1:]
+
+		// Find the closing quote, skipping escaped ones.
+		end := 1
+		for end < len(s) && s[end] != '"' {
+			if s[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end >= len(s) {
+			return kv
+		}
+		value, err := strconv.Unquote(s[:end+1])
+		if err != nil {
+			return kv
+		}
+		if kv == nil {
+			kv = make(map[string]string)
+		}
+		kv[key] = value
+		s = s[end+1:]
+	}
+}
+
+// WriteJSON writes r as indented JSON.
+func (r Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteTree writes r as an indented, human-readable tree.
+func (r Report) WriteTree(w io.Writer) error {
+	tw := &treeWriter{w: w}
+	tw.line(0, "%s (%s)", r.Type, r.Kind)
+	if len(r.Fields) > 0 {
+		tw.line(1, "fields")
+		tw.fields(2, r.Fields)
+	}
+	tw.methods("methods of "+r.Type, r.ValueMethods)
+	tw.methods("methods of *"+r.Type, r.PointerMethods)
+	if len(r.Implements) > 0 {
+		tw.line(1, "implements")
+		for _, impl := range r.Implements {
+			tw.line(2, "%s: %s %s, *%s %s", impl.Interface,
+				r.Type, yesNo(impl.Value), r.Type, yesNo(impl.Pointer))
+		}
+	}
+	return tw.err
+}
+
+// treeWriter keeps the first write error so WriteTree can check it once.
+type treeWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (tw *treeWriter) line(depth int, format string, args ...any) {
+	if tw.err != nil {
+		return
+	}
+	_, tw.err = fmt.Fprintf(tw.w, strings.Repeat("  ", depth)+format+"\n", args...)
+}
+
+func (tw *treeWriter) fields(depth int, fs []Field) {
+	for _, f := range fs {
+		name := f.Name
+		if f.Embedded {
+			name += " (embedded)"
+		}
+		if !f.Exported {
+			name += " (unexported)"
+		}
+		tw.line(depth, "%s %s", name, f.Type)
+		for _, key := range sortedKeys(f.Tags) {
+			tw.line(depth+1, "tag %s: %q", key, f.Tags[key])
+		}
+		tw.fields(depth+1, f.Fields)
+	}
+}
+
+func (tw *treeWriter) methods(title string, ms []Method) {
+	tw.line(1, "%s (%d)", title, len(ms))
+	for _, m := range ms {
+		tw.line(2, "%s%s", m.Name, m.Signature)
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
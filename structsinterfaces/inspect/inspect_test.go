@@ -0,0 +1,81 @@
+package inspect_test
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/salmomascarenhas/go-study-exercises/structsinterfaces/inspect"
+)
+
+type counter struct {
+	Name string `json:"name" validate:"required"`
+	n    int
+}
+
+func (c counter) String() string                     { return c.Name }
+func (c *counter) Add(n int, labels ...string) error { c.n += n; return nil }
+
+func TestTypeInterfaceSignatures(t *testing.T) {
+	tests := []struct {
+		typ  reflect.Type
+		want []inspect.Method
+	}{
+		{reflect.TypeFor[io.Writer](), []inspect.Method{{Name: "Write", Signature: "([]uint8) (int, error)"}}},
+		{reflect.TypeFor[fmt.Stringer](), []inspect.Method{{Name: "String", Signature: "() string"}}},
+		{reflect.TypeFor[io.ReadWriteCloser](), []inspect.Method{
+			{Name: "Close", Signature: "() error"},
+			{Name: "Read", Signature: "([]uint8) (int, error)"},
+			{Name: "Write", Signature: "([]uint8) (int, error)"},
+		}},
+	}
+	for _, tt := range tests {
+		r := inspect.Type(tt.typ)
+		if !reflect.DeepEqual(r.ValueMethods, tt.want) {
+			t.Errorf("%v methods = %+v, want %+v", tt.typ, r.ValueMethods, tt.want)
+		}
+		if len(r.PointerMethods) != 0 {
+			t.Errorf("*%v has methods %+v, want none", tt.typ, r.PointerMethods)
+		}
+	}
+}
+
+func TestOfStruct(t *testing.T) {
+	r := inspect.Of(&counter{}, reflect.TypeFor[fmt.Stringer](), reflect.TypeFor[interface{ Add(int, ...string) error }]())
+
+	wantValue := []inspect.Method{{Name: "String", Signature: "() string"}}
+	wantPointer := []inspect.Method{
+		{Name: "Add", Signature: "(int, ...string) error"},
+		{Name: "String", Signature: "() string"},
+	}
+	if !reflect.DeepEqual(r.ValueMethods, wantValue) {
+		t.Errorf("ValueMethods = %+v, want %+v", r.ValueMethods, wantValue)
+	}
+	if !reflect.DeepEqual(r.PointerMethods, wantPointer) {
+		t.Errorf("PointerMethods = %+v, want %+v", r.PointerMethods, wantPointer)
+	}
+
+	wantImpl := []inspect.Implementation{
+		{Interface: "fmt.Stringer", Value: true, Pointer: true},
+		{Interface: "interface { Add(int, ...string) error }", Value: false, Pointer: true},
+	}
+	if !reflect.DeepEqual(r.Implements, wantImpl) {
+		t.Errorf("Implements = %+v, want %+v", r.Implements, wantImpl)
+	}
+
+	if len(r.Fields) != 2 || r.Fields[0].Tags["validate"] != "required" || r.Fields[1].Exported {
+		t.Errorf("Fields = %+v", r.Fields)
+	}
+}
+
+func TestWriteTreeInterface(t *testing.T) {
+	var b strings.Builder
+	if err := inspect.Type(reflect.TypeFor[io.Writer]()).WriteTree(&b); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "Write([]uint8) (int, error)") {
+		t.Errorf("tree has no Write signature:\n%s", b.String())
+	}
+}
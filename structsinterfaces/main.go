@@ -2,24 +2,56 @@ package main
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"time"
 )
 
 type Greeter interface {
 	Greet()
 }
 
+// WriterGreeter renders its greeting into w instead of stdout and reports
+// any write failure.
+type WriterGreeter interface {
+	GreetTo(w io.Writer) error
+}
+
+// Person is the profile model from the struct tags notes below. The json
+// tags drive the JSON, YAML and TOML encodings alike (see Marshal) and the
+// validate tags are checked by Validate.
 type Person struct {
-	Name string
+	ID        uint64    `json:"id"`
+	Name      string    `json:"name" validate:"required,min=3,max=64"`
+	Birthdate time.Time `json:"birthdate" validate:"past"`
+	IsAdmin   bool      `json:"is_admin"`
+	Email     string    `json:"email" validate:"email"`
+	Phone     string    `json:"phone" validate:"e164"`
+
+	// asOf is the instant CurrentAge measures age at, moved forward by
+	// HaveBirthday; until then, and once real time passes it, time.Now.
+	asOf time.Time
 }
 
+// localizer renders greetings in the locale picked from the environment.
+var localizer = MustLoadCatalog(EmbeddedLocales()).Localizer(LocaleFromEnv())
+
 func (p Person) Greet() {
-	fmt.Println("Hello, my name is", p.Name)
+	_ = p.GreetTo(os.Stdout)
+}
+
+func (p Person) GreetTo(w io.Writer) error {
+	return writeMessage(w, "greeting", p.Name)
+}
+
+// writeMessage renders the catalog message key for name as one line of w.
+func writeMessage(w io.Writer, key, name string) error {
+	_, err := fmt.Fprintln(w, localizer.Translate(key, Args{Vars: map[string]string{"name": name}}))
+	return err
 }
 
 func main() {
-	var person Greeter
-	person = Person{Name: "Salmo Mascarenhas"}
-	person.Greet()
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
 }
 
 // -> Interfaces in Go <-
@@ -115,6 +147,11 @@ func main() {
 // person2 := Person{Name: "John Doe", Age: 30, Email: "john@example.com"}
 // fmt.Println(person1 == person2)
 //
+// == only compiles while every field is comparable: a slice or map field
+// breaks it. It also compares time.Time fields by their internal
+// representation, not the instant. Person.Equal, Hash and Compare (see
+// equality.go) don't have either problem.
+//
 
 // -> Struct Tags
 //
@@ -154,4 +191,24 @@ func main() {
 // Here's an example to illustrate the difference between a value receiver and a
 // pointer receiver:
 //
-// 
\ No newline at end of file
+// type User struct {
+// 	ID uint64
+// }
+// func (u User) PrintID() {
+// 	fmt.Println(u.ID)
+// }
+// func (u *User) SetID(id uint64) {
+// 	u.ID = id
+// }
+// func main() {
+// 	user := User{ID: 1}
+// 	user.SetID(2) // Go takes &user automatically.
+// 	user.PrintID() // 2
+// }
+// SetID changes the user because it receives a pointer to it. A value receiver
+// would get a copy, and the change would be lost when the method returns.
+//
+// The receiver also decides the method set: a pointer type has the methods of
+// both receivers, a value type only the value receiver ones. That's why only
+// *Person satisfies Mutator (see receivers.go), while Person and *Person both
+// satisfy Builder, whose With* methods return an updated copy instead.
@@ -0,0 +1,81 @@
+package main
+
+import "time"
+
+// Mutator is implemented by *Person only: its methods have pointer
+// receivers, so they change the Person they are called on.
+type Mutator interface {
+	Rename(name string)
+	SetEmail(email string)
+	HaveBirthday()
+}
+
+// Builder is implemented by both Person and *Person: its methods have value
+// receivers and return an updated copy, leaving the original untouched.
+type Builder interface {
+	WithName(name string) Person
+	WithEmail(email string) Person
+	WithBirthdate(birthdate time.Time) Person
+}
+
+var (
+	_ Mutator = (*Person)(nil)
+	_ Builder = Person{}
+	_ Builder = (*Person)(nil)
+)
+
+func (p *Person) Rename(name string) {
+	p.Name = name
+}
+
+func (p *Person) SetEmail(email string) {
+	p.Email = email
+}
+
+// HaveBirthday ages p by a year: it moves the clock CurrentAge reads to p's
+// next birthday. Birthdate doesn't change, so neither do Equal and Hash. It
+// does nothing if p has no birthdate.
+func (p *Person) HaveBirthday() {
+	if p.Birthdate.IsZero() {
+		return
+	}
+	p.asOf = p.Birthdate.AddDate(p.CurrentAge()+1, 0, 0)
+}
+
+// CurrentAge returns p's age now, counting the birthdays HaveBirthday has
+// advanced it through.
+func (p Person) CurrentAge() int {
+	now := time.Now()
+	if p.asOf.After(now) {
+		now = p.asOf
+	}
+	return p.Age(now)
+}
+
+// Age returns p's age in whole years at now.
+func (p Person) Age(now time.Time) int {
+	if p.Birthdate.IsZero() {
+		return 0
+	}
+	years := now.Year() - p.Birthdate.Year()
+	if now.Month() < p.Birthdate.Month() ||
+		now.Month() == p.Birthdate.Month() && now.Day() < p.Birthdate.Day() {
+		years--
+	}
+	return years
+}
+
+func (p Person) WithName(name string) Person {
+	p.Name = name
+	return p
+}
+
+func (p Person) WithEmail(email string) Person {
+	p.Email = email
+	return p
+}
+
+func (p Person) WithBirthdate(birthdate time.Time) Person {
+	p.Birthdate = birthdate
+	return p
+}
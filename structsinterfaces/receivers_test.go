@@ -0,0 +1,154 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReceiverMethodSets(t *testing.T) {
+	person, ptr := reflect.TypeFor[Person](), reflect.TypeFor[*Person]()
+	mutator, builder := reflect.TypeFor[Mutator](), reflect.TypeFor[Builder]()
+
+	if person.Implements(mutator) {
+		t.Error("Person implements Mutator, but its mutators have pointer receivers")
+	}
+	if !ptr.Implements(mutator) {
+		t.Error("*Person doesn't implement Mutator")
+	}
+	if !person.Implements(builder) || !ptr.Implements(builder) {
+		t.Error("Person and *Person should both implement Builder")
+	}
+}
+
+func TestMutatorChangesOriginal(t *testing.T) {
+	p := Person{Name: "Salmo", Email: "old@example.com"}
+	var m Mutator = &p
+	m.Rename("Ana")
+	m.SetEmail("ana@example.com")
+	if p.Name != "Ana" || p.Email != "ana@example.com" {
+		t.Errorf("after mutating through &p, p = %+v", p)
+	}
+}
+
+func TestBuilderLeavesOriginal(t *testing.T) {
+	birthdate := time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC)
+	p := Person{Name: "Salmo", Email: "salmo@example.com"}
+	var b Builder = &p // through a pointer, the copy is still made
+
+	q := b.WithName("Ana").WithEmail("ana@example.com").WithBirthdate(birthdate)
+	if p.Name != "Salmo" || p.Email != "salmo@example.com" || !p.Birthdate.IsZero() {
+		t.Errorf("With* changed the original: %+v", p)
+	}
+	want := Person{Name: "Ana", Email: "ana@example.com", Birthdate: birthdate}
+	if !q.Equal(want) {
+		t.Errorf("built %+v, want %+v", q, want)
+	}
+}
+
+func TestValueCopiesDontAlias(t *testing.T) {
+	p := Person{Name: "Salmo", Email: "salmo@example.com"}
+
+	q := p
+	q.Rename("Ana")
+	q.SetEmail("ana@example.com")
+	if p.Name != "Salmo" || p.Email != "salmo@example.com" {
+		t.Errorf("mutating a copy changed the original: %+v", p)
+	}
+
+	// A value receiver gets its own copy too: mutating it inside the
+	// method, as WithName does, never reaches the caller's Person.
+	_ = p.WithName("Ana")
+	if p.Name != "Salmo" {
+		t.Errorf("WithName changed its receiver: %+v", p)
+	}
+
+	// Values stored in a slice are copies of p, not references to it.
+	people := []Person{p, p}
+	people[0].Rename("Ana")
+	if people[1].Name != "Salmo" || p.Name != "Salmo" {
+		t.Errorf("renaming people[0] changed people[1] = %+v or p = %+v", people[1], p)
+	}
+}
+
+func TestAge(t *testing.T) {
+	p := Person{Birthdate: time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC)}
+	leap := Person{Birthdate: time.Date(2000, 2, 29, 0, 0, 0, 0, time.UTC)}
+	tests := []struct {
+		name string
+		p    Person
+		now  time.Time
+		want int
+	}{
+		{"day before birthday", p, time.Date(2024, 5, 16, 0, 0, 0, 0, time.UTC), 33},
+		{"on birthday", p, time.Date(2024, 5, 17, 0, 0, 0, 0, time.UTC), 34},
+		{"month after", p, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), 34},
+		{"leap, Feb 28", leap, time.Date(2023, 2, 28, 0, 0, 0, 0, time.UTC), 22},
+		{"leap, Mar 1", leap, time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC), 23},
+		{"zero birthdate", Person{}, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := tt.p
+			if got := tt.p.Age(tt.now); got != tt.want {
+				t.Errorf("Age(%s) = %d, want %d", tt.now.Format(time.DateOnly), got, tt.want)
+			}
+			if !tt.p.Equal(before) || tt.p.Hash() != before.Hash() {
+				t.Error("Age changed the person")
+			}
+		})
+	}
+}
+
+func TestHaveBirthday(t *testing.T) {
+	// Birthday tomorrow, 30 years ago: 29 today.
+	birthdate := time.Now().AddDate(-30, 0, 1)
+	p := Person{Name: "Salmo", Birthdate: birthdate}
+	original := p
+	if got := p.CurrentAge(); got != 29 {
+		t.Fatalf("CurrentAge = %d, want 29", got)
+	}
+
+	var m Mutator = &p
+	m.HaveBirthday()
+	if got := p.CurrentAge(); got != 30 {
+		t.Errorf("after one birthday, CurrentAge = %d, want 30", got)
+	}
+	p.HaveBirthday()
+	if got := p.CurrentAge(); got != 31 {
+		t.Errorf("after two birthdays, CurrentAge = %d, want 31", got)
+	}
+
+	if !p.Birthdate.Equal(birthdate) {
+		t.Errorf("HaveBirthday changed Birthdate to %v", p.Birthdate)
+	}
+	if !p.Equal(original) || p.Hash() != original.Hash() {
+		t.Error("HaveBirthday changed the person's identity")
+	}
+	if got := original.CurrentAge(); got != 29 {
+		t.Errorf("the copy taken before aged too: CurrentAge = %d", got)
+	}
+	if got := p.Age(time.Now()); got != 29 {
+		t.Errorf("Age(now) = %d, want 29: it reads the clock it is given", got)
+	}
+}
+
+func TestHaveBirthdayLeapDay(t *testing.T) {
+	p := Person{Birthdate: time.Date(2000, 2, 29, 0, 0, 0, 0, time.UTC)}
+	want := p.CurrentAge()
+	for range 4 {
+		p.HaveBirthday()
+		want++
+		if got := p.CurrentAge(); got != want {
+			t.Fatalf("CurrentAge = %d, want %d", got, want)
+		}
+	}
+}
+
+func TestHaveBirthdayWithoutBirthdate(t *testing.T) {
+	var p Person
+	p.HaveBirthday()
+	if got := p.CurrentAge(); got != 0 || p != (Person{}) {
+		t.Errorf("HaveBirthday on no birthdate: CurrentAge %d, %+v", got, p)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultStyle is the style that greets with Person's own GreetTo.
+const DefaultStyle = "default"
+
+// StyleFactory builds the greeter for a style around p.
+type StyleFactory func(p Person) WriterGreeter
+
+var (
+	stylesMu sync.RWMutex
+	styles   = make(map[string]StyleFactory)
+)
+
+// UnknownStyleError is returned by LookupStyle for a name nobody registered.
+type UnknownStyleError struct {
+	Name string
+}
+
+func (e *UnknownStyleError) Error() string {
+	return fmt.Sprintf("unknown greeting style %q (available: %s)", e.Name, strings.Join(Styles(), ", "))
+}
+
+// RegisterStyle makes a greeting style available under name. It is meant to
+// be called from init and panics if name is empty, factory is nil or the
+// name is already taken.
+func RegisterStyle(name string, factory StyleFactory) {
+	stylesMu.Lock()
+	defer stylesMu.Unlock()
+	if name == "" || factory == nil {
+		panic("greeter: RegisterStyle needs a name and a factory")
+	}
+	if _, dup := styles[name]; dup {
+		panic("greeter: RegisterStyle called twice for " + name)
+	}
+	styles[name] = factory
+}
+
+// LookupStyle returns the factory registered under name, or an
+// *UnknownStyleError.
+func LookupStyle(name string) (StyleFactory, error) {
+	stylesMu.RLock()
+	factory, ok := styles[name]
+	stylesMu.RUnlock()
+	if !ok {
+		return nil, &UnknownStyleError{Name: name}
+	}
+	return factory, nil
+}
+
+// Styles lists the registered style names, sorted.
+func Styles() []string {
+	stylesMu.RLock()
+	defer stylesMu.RUnlock()
+	names := make([]string, 0, len(styles))
+	for name := range styles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	RegisterStyle(DefaultStyle, func(p Person) WriterGreeter { return p })
+	RegisterStyle("formal", func(p Person) WriterGreeter { return FormalGreeter{Person: p} })
+	RegisterStyle("casual", func(p Person) WriterGreeter { return CasualGreeter{Person: p} })
+	RegisterStyle("robot", func(p Person) WriterGreeter { return RobotGreeter{Person: p} })
+	RegisterStyle("time", func(p Person) WriterGreeter { return TimeOfDayGreeter{Person: p, Now: time.Now} })
+}
+
+type FormalGreeter struct {
+	Person Person
+}
+
+func (g FormalGreeter) GreetTo(w io.Writer) error {
+	return writeMessage(w, "greeting.formal", g.Person.Name)
+}
+
+type CasualGreeter struct {
+	Person Person
+}
+
+func (g CasualGreeter) GreetTo(w io.Writer) error {
+	return writeMessage(w, "greeting.casual", g.Person.Name)
+}
+
+// RobotGreeter shouts its greeting, name included, in upper case.
+type RobotGreeter struct {
+	Person Person
+}
+
+func (g RobotGreeter) GreetTo(w io.Writer) error {
+	return writeMessage(w, "greeting.robot", strings.ToUpper(g.Person.Name))
+}
+
+// TimeOfDayGreeter says good morning, afternoon or evening depending on the
+// hour returned by Now.
+type TimeOfDayGreeter struct {
+	Person Person
+	Now    func() time.Time
+}
+
+func (g TimeOfDayGreeter) GreetTo(w io.Writer) error {
+	key := "greeting.evening"
+	switch hour := g.Now().Hour(); {
+	case hour >= 5 && hour < 12:
+		key = "greeting.morning"
+	case hour >= 12 && hour < 18:
+		key = "greeting.afternoon"
+	}
+	return writeMessage(w, key, g.Person.Name)
+}
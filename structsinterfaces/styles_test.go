@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStyles(t *testing.T) {
+	want := []string{"casual", DefaultStyle, "formal", "robot", "template", "time"}
+	if got := Styles(); !slices.Equal(got, want) {
+		t.Errorf("Styles = %q, want %q", got, want)
+	}
+	for _, name := range want {
+		if _, err := LookupStyle(name); err != nil {
+			t.Errorf("LookupStyle(%q): %v", name, err)
+		}
+	}
+}
+
+func TestLookupUnknownStyle(t *testing.T) {
+	factory, err := LookupStyle("nope")
+	var unknown *UnknownStyleError
+	if !errors.As(err, &unknown) || factory != nil {
+		t.Fatalf("LookupStyle(nope) = %v, %v; want a nil factory and an *UnknownStyleError", factory, err)
+	}
+	if unknown.Name != "nope" {
+		t.Errorf("Name = %q, want nope", unknown.Name)
+	}
+	if !strings.Contains(err.Error(), strings.Join(Styles(), ", ")) {
+		t.Errorf("error %q doesn't list the available styles", err)
+	}
+}
+
+func TestRegisterStyle(t *testing.T) {
+	RegisterStyle("test", func(p Person) WriterGreeter { return RobotGreeter{Person: p} })
+	t.Cleanup(func() {
+		stylesMu.Lock()
+		delete(styles, "test")
+		stylesMu.Unlock()
+	})
+
+	if !slices.Contains(Styles(), "test") || !slices.IsSorted(Styles()) {
+		t.Errorf("Styles = %q, want test among them, sorted", Styles())
+	}
+	factory, err := LookupStyle("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := factory(Person{Name: "Salmo"}).GreetTo(&buf); err != nil || !strings.Contains(buf.String(), "SALMO") {
+		t.Errorf("test style greeted %q, %v", buf.String(), err)
+	}
+}
+
+func TestRegisterStylePanics(t *testing.T) {
+	robot := func(p Person) WriterGreeter { return RobotGreeter{Person: p} }
+	tests := map[string]func(){
+		"duplicate":   func() { RegisterStyle("formal", robot) },
+		"empty name":  func() { RegisterStyle("", robot) },
+		"nil factory": func() { RegisterStyle("nil", nil) },
+	}
+	for name, register := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("RegisterStyle didn't panic")
+				}
+			}()
+			register()
+		})
+	}
+	if _, err := LookupStyle("nil"); err == nil {
+		t.Error("a style that panicked was registered anyway")
+	}
+}
+
+func TestTimeOfDayGreeter(t *testing.T) {
+	tests := []struct {
+		hour, min int
+		want      string
+	}{
+		{0, 0, "Good evening"},
+		{4, 59, "Good evening"},
+		{5, 0, "Good morning"},
+		{11, 59, "Good morning"},
+		{12, 0, "Good afternoon"},
+		{17, 59, "Good afternoon"},
+		{18, 0, "Good evening"},
+		{23, 59, "Good evening"},
+	}
+	for _, tt := range tests {
+		now := time.Date(2024, 6, 1, tt.hour, tt.min, 0, 0, time.UTC)
+		var buf bytes.Buffer
+		g := TimeOfDayGreeter{Person: Person{Name: "Salmo"}, Now: func() time.Time { return now }}
+		if err := g.GreetTo(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if want := tt.want + ", my name is Salmo\n"; buf.String() != want {
+			t.Errorf("at %s: %q, want %q", now.Format("15:04"), buf.String(), want)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"embed"
+	"errors"
+	htmltemplate "html/template"
+	"io"
+	"io/fs"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:embed templates/*.tmpl
+var templateFiles embed.FS
+
+// templateFuncs are the helpers available to every greeting template.
+var templateFuncs = map[string]any{
+	"title":    titleCase,
+	"initials": initials,
+	"age": func(birthdate time.Time) int {
+		return Person{Birthdate: birthdate}.Age(time.Now())
+	},
+}
+
+// executor is what text/template and html/template templates have in
+// common.
+type executor interface {
+	Execute(w io.Writer, data any) error
+}
+
+// TemplateSet loads greeting templates by name from a file system,
+// compiling each one on first use and caching it. Names ending in ".html"
+// or ".html.tmpl" are parsed with html/template and escaped accordingly;
+// anything else uses text/template.
+type TemplateSet struct {
+	fsys fs.FS
+
+	mu    sync.Mutex
+	cache map[string]executor
+}
+
+func NewTemplateSet(fsys fs.FS) *TemplateSet {
+	return &TemplateSet{fsys: fsys, cache: make(map[string]executor)}
+}
+
+// EmbeddedTemplates returns the set of templates shipped with the binary.
+func EmbeddedTemplates() *TemplateSet {
+	sub, err := fs.Sub(templateFiles, "templates")
+	if err != nil {
+		panic(err)
+	}
+	return NewTemplateSet(sub)
+}
+
+// TemplatesFromDir returns the set of templates found under dir.
+func TemplatesFromDir(dir string) *TemplateSet {
+	return NewTemplateSet(os.DirFS(dir))
+}
+
+// Render executes the template name with data into w.
+func (s *TemplateSet) Render(w io.Writer, name string, data any) error {
+	tmpl, err := s.lookup(name)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, data)
+}
+
+func (s *TemplateSet) lookup(name string) (executor, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if tmpl, ok := s.cache[name]; ok {
+		return tmpl, nil
+	}
+
+	src, err := fs.ReadFile(s.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	var tmpl executor
+	if strings.HasSuffix(name, ".html") || strings.HasSuffix(name, ".html.tmpl") {
+		tmpl, err = htmltemplate.New(name).Funcs(templateFuncs).Parse(string(src))
+	} else {
+		tmpl, err = template.New(name).Funcs(templateFuncs).Parse(string(src))
+	}
+	if err != nil {
+		return nil, newTemplateError(name, err)
+	}
+	s.cache[name] = tmpl
+	return tmpl, nil
+}
+
+// TemplateError is a template that failed to parse.
+type TemplateError struct {
+	Name string
+	// Line is 0 when the parser didn't report one.
+	Line int
+	Err  error
+}
+
+func (e *TemplateError) Error() string {
+	if e.Line > 0 {
+		return "template " + e.Name + ":" + strconv.Itoa(e.Line) + ": " + e.Err.Error()
+	}
+	return "template " + e.Name + ": " + e.Err.Error()
+}
+
+func (e *TemplateError) Unwrap() error { return e.Err }
+
+// parseErrorPrefix matches the "template: name:line:" prefix that both
+// template packages put on parse errors.
+var parseErrorPrefix = regexp.MustCompile(`^(?:html/)?template: ([^:]+):(\d+):(?:\d+:)? ?`)
+
+func newTemplateError(name string, err error) *TemplateError {
+	msg := err.Error()
+	m := parseErrorPrefix.FindStringSubmatchIndex(msg)
+	if m == nil {
+		return &TemplateError{Name: name, Err: err}
+	}
+	line, _ := strconv.Atoi(msg[m[4]:m[5]])
+	return &TemplateError{Name: name, Line: line, Err: errors.New(msg[m[1]:])}
+}
+
+// TemplateGreeter greets by rendering a template of Templates with Person as
+// its data.
+type TemplateGreeter struct {
+	Person    Person
+	Templates *TemplateSet
+	Name      string
+}
+
+func (g TemplateGreeter) GreetTo(w io.Writer) error {
+	return g.Templates.Render(w, g.Name, g.Person)
+}
+
+func init() {
+	templates := EmbeddedTemplates()
+	RegisterStyle("template", func(p Person) WriterGreeter {
+		return TemplateGreeter{Person: p, Templates: templates, Name: "greeting.tmpl"}
+	})
+}
+
+func titleCase(s string) string {
+	words := strings.Fields(s)
+	for i, word := range words {
+		r, size := utf8.DecodeRuneInString(word)
+		words[i] = string(unicode.ToTitle(r)) + strings.ToLower(word[size:])
+	}
+	return strings.Join(words, " ")
+}
+
+func initials(s string) string {
+	var b strings.Builder
+	for _, word := range strings.Fields(s) {
+		r, _ := utf8.DecodeRuneInString(word)
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
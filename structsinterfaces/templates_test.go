@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func renderTemplate(t *testing.T, s *TemplateSet, name string, data any) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := s.Render(&buf, name, data); err != nil {
+		t.Fatalf("Render(%s): %v", name, err)
+	}
+	return buf.String()
+}
+
+func TestTemplateEscaping(t *testing.T) {
+	const src = `<p>{{.Name}}</p>`
+	s := NewTemplateSet(fstest.MapFS{
+		"a.tmpl":      {Data: []byte(src)},
+		"a.html":      {Data: []byte(src)},
+		"a.html.tmpl": {Data: []byte(src)},
+		"a.htm":       {Data: []byte(src)},
+	})
+	p := Person{Name: `<b>"Tom" & Jerry</b>`}
+	tests := map[string]string{
+		"a.tmpl":      `<p><b>"Tom" & Jerry</b></p>`,
+		"a.htm":       `<p><b>"Tom" & Jerry</b></p>`,
+		"a.html":      `<p>&lt;b&gt;&#34;Tom&#34; &amp; Jerry&lt;/b&gt;</p>`,
+		"a.html.tmpl": `<p>&lt;b&gt;&#34;Tom&#34; &amp; Jerry&lt;/b&gt;</p>`,
+	}
+	for name, want := range tests {
+		if got := renderTemplate(t, s, name, p); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestTemplateCache(t *testing.T) {
+	fsys := fstest.MapFS{"hi.tmpl": {Data: []byte("hi {{.Name}}")}}
+	s := NewTemplateSet(fsys)
+	if got := renderTemplate(t, s, "hi.tmpl", Person{Name: "Ana"}); got != "hi Ana" {
+		t.Fatalf("got %q", got)
+	}
+
+	// The template is compiled once: later changes, even removing the
+	// file, go unnoticed by this set.
+	fsys["hi.tmpl"] = &fstest.MapFile{Data: []byte("bye {{.Name}}")}
+	if got := renderTemplate(t, s, "hi.tmpl", Person{Name: "Ana"}); got != "hi Ana" {
+		t.Errorf("after changing the file: got %q, want the cached template", got)
+	}
+	delete(fsys, "hi.tmpl")
+	if got := renderTemplate(t, s, "hi.tmpl", Person{Name: "Bia"}); got != "hi Bia" {
+		t.Errorf("after removing the file: got %q, want the cached template", got)
+	}
+
+	if err := NewTemplateSet(fsys).Render(&bytes.Buffer{}, "hi.tmpl", Person{}); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("a new set: got %v, want fs.ErrNotExist", err)
+	}
+}
+
+func TestTemplateHelpers(t *testing.T) {
+	birthdate := time.Now().AddDate(-30, 0, -1)
+	s := NewTemplateSet(fstest.MapFS{
+		"h.tmpl": {Data: []byte(`{{title .Name}}|{{initials .Name}}|{{age .Birthdate}}`)},
+	})
+	got := renderTemplate(t, s, "h.tmpl", Person{Name: "salmo DE mascarenhas", Birthdate: birthdate})
+	if want := "Salmo De Mascarenhas|SDM|30"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	for in, want := range map[string]string{"": "", "  ana  ": "Ana", "élio ángelo": "Élio Ángelo"} {
+		if got := titleCase(in); got != want {
+			t.Errorf("titleCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+	for in, want := range map[string]string{"": "", "ana lima": "AL", "élio ángelo": "ÉÁ"} {
+		if got := initials(in); got != want {
+			t.Errorf("initials(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestTemplateParseError(t *testing.T) {
+	s := NewTemplateSet(fstest.MapFS{
+		"bad.tmpl":      {Data: []byte("line 1\nline 2\n{{.Name | nosuchfunc}}\nline 4\n")},
+		"bad.html.tmpl": {Data: []byte("<p>\n{{if .Name}}\n</p>\n")},
+	})
+	tests := map[string]int{"bad.tmpl": 3, "bad.html.tmpl": 4}
+	for name, line := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := s.Render(&bytes.Buffer{}, name, Person{})
+			var tmplErr *TemplateError
+			if !errors.As(err, &tmplErr) {
+				t.Fatalf("got %v, want a *TemplateError", err)
+			}
+			if tmplErr.Name != name || tmplErr.Line != line {
+				t.Errorf("got Name %q, Line %d; want %q, %d", tmplErr.Name, tmplErr.Line, name, line)
+			}
+			if prefix := "template " + name + ":"; !strings.HasPrefix(err.Error(), prefix) || strings.Count(err.Error(), "template") != 1 {
+				t.Errorf("error %q, want it to start with %q and name the template once", err, prefix)
+			}
+		})
+	}
+
+	// A failed template isn't cached: the error comes back every time.
+	if err := s.Render(&bytes.Buffer{}, "bad.tmpl", Person{}); err == nil {
+		t.Error("second Render of a bad template succeeded")
+	}
+}
+
+func TestTemplateMissing(t *testing.T) {
+	err := NewTemplateSet(fstest.MapFS{}).Render(&bytes.Buffer{}, "nope.tmpl", Person{})
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("got %v, want fs.ErrNotExist", err)
+	}
+}
+
+func TestEmbeddedTemplates(t *testing.T) {
+	var buf bytes.Buffer
+	err := TemplateGreeter{Person: Person{Name: "salmo"}, Templates: EmbeddedTemplates(), Name: "greeting.tmpl"}.GreetTo(&buf)
+	if err != nil || !strings.Contains(buf.String(), "Salmo") {
+		t.Errorf("greeting.tmpl = %q, %v", buf.String(), err)
+	}
+}
@@ -0,0 +1,83 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"strconv"
+	"unicode/utf8"
+)
+
+var e164Pattern = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+
+func required(v reflect.Value, _ string) error {
+	if v.IsZero() {
+		return errors.New("is required")
+	}
+	return nil
+}
+
+func email(v reflect.Value, _ string) error {
+	if v.Kind() != reflect.String {
+		return fmt.Errorf("email rule does not apply to %s", v.Type())
+	}
+	addr, err := mail.ParseAddress(v.String())
+	if err != nil || addr.Address != v.String() {
+		return errors.New("must be a valid email address")
+	}
+	return nil
+}
+
+func e164(v reflect.Value, _ string) error {
+	if v.Kind() != reflect.String {
+		return fmt.Errorf("e164 rule does not apply to %s", v.Type())
+	}
+	if !e164Pattern.MatchString(v.String()) {
+		return errors.New("must be an E.164 phone number, e.g. +5511999999999")
+	}
+	return nil
+}
+
+func minRule(v reflect.Value, param string) error {
+	return bound(v, param, "min", func(n, limit float64) bool { return n >= limit })
+}
+
+func maxRule(v reflect.Value, param string) error {
+	return bound(v, param, "max", func(n, limit float64) bool { return n <= limit })
+}
+
+// bound compares the size of v with the limit in param: the rune count of
+// strings, the length of slices, arrays and maps, or the value of numbers.
+func bound(v reflect.Value, param, rule string, ok func(n, limit float64) bool) error {
+	limit, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return fmt.Errorf("%s rule needs a numeric parameter, got %q", rule, param)
+	}
+
+	var n float64
+	var unit string
+	switch v.Kind() {
+	case reflect.String:
+		n, unit = float64(utf8.RuneCountInString(v.String())), " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		n, unit = float64(v.Len()), " items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		n = v.Float()
+	default:
+		return fmt.Errorf("%s rule does not apply to %s", rule, v.Type())
+	}
+
+	if ok(n, limit) {
+		return nil
+	}
+	if rule == "min" {
+		return fmt.Errorf("must be at least %s%s", param, unit)
+	}
+	return fmt.Errorf("must be at most %s%s", param, unit)
+}
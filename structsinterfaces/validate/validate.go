@@ -0,0 +1,202 @@
+// Package validate checks struct fields against rules declared in
+// `validate:"..."` struct tags, e.g.
+//
+//	type User struct {
+//		Name  string `validate:"required,min=3,max=64"`
+//		Email string `validate:"required,email"`
+//		Phone string `validate:"e164"`
+//	}
+//
+// Rules are separated by commas; a rule takes a parameter after "=".
+// Every rule except "required" is skipped for zero values, so a field is
+// optional unless it says otherwise. Nested structs, pointers, slices,
+// arrays and maps are walked, and each violation is reported with the path
+// of the field that caused it.
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrUnknownRule is wrapped by the FieldError of a tag naming a rule that
+// was never registered.
+var ErrUnknownRule = errors.New("unknown validation rule")
+
+// Rule checks v against param, the text after "=" in the tag (empty when
+// there is none), and returns an error describing the violation.
+type Rule func(v reflect.Value, param string) error
+
+var (
+	rulesMu sync.RWMutex
+	rules   = map[string]Rule{
+		"required": required,
+		"email":    email,
+		"e164":     e164,
+		"min":      minRule,
+		"max":      maxRule,
+	}
+)
+
+// Register adds a custom rule under name. It panics if name is empty, rule
+// is nil or the name is already taken, built-in rules included.
+func Register(name string, rule Rule) {
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	if name == "" || rule == nil {
+		panic("validate: Register needs a name and a rule")
+	}
+	if _, dup := rules[name]; dup {
+		panic("validate: Register called twice for rule " + name)
+	}
+	rules[name] = rule
+}
+
+// Rules lists the registered rule names, sorted.
+func Rules() []string {
+	rulesMu.RLock()
+	defer rulesMu.RUnlock()
+	names := make([]string, 0, len(rules))
+	for name := range rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FieldError is one rule violation.
+type FieldError struct {
+	// Path locates the field from the validated value, e.g.
+	// "Contacts[1].Email".
+	Path string
+	// Rule and Param are the tag rule that failed.
+	Rule  string
+	Param string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+func (e *FieldError) Unwrap() error { return e.Err }
+
+// Struct validates v, a struct or a pointer to one. All violations are
+// returned together as an errors.Join of *FieldError, or nil if v is valid.
+func Struct(v any) error {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return errors.New("validate: nil value")
+	}
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return errors.New("validate: nil pointer")
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("validate: %s is not a struct", rv.Type())
+	}
+	var errs []error
+	walk(rv, "", &errs, make(map[visit]bool))
+	return errors.Join(errs...)
+}
+
+// visit identifies a pointer, map or slice that walk is inside of. The type
+// is part of it because a struct and its first field share an address.
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+// walk validates the tagged fields of every struct reachable from v.
+// active holds the pointers, maps and slices on the path from the root to
+// v, so that a cycle is walked once instead of forever; values shared by
+// two fields, without a cycle, are still checked under both paths.
+func walk(v reflect.Value, path string, errs *[]error, active map[visit]bool) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return
+		}
+		key := visit{ptr: v.Pointer(), typ: v.Type(), len: -1}
+		if v.Kind() == reflect.Slice {
+			key.len = v.Len()
+		}
+		if active[key] {
+			return
+		}
+		active[key] = true
+		defer delete(active, key)
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			walk(v.Elem(), path, errs, active)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			walk(v.Index(i), fmt.Sprintf("%s[%d]", path, i), errs, active)
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		for _, key := range keys {
+			walk(v.MapIndex(key), fmt.Sprintf("%s[%v]", path, key.Interface()), errs, active)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			tag := field.Tag.Get("validate")
+			if tag == "-" {
+				continue
+			}
+			fieldPath := field.Name
+			if path != "" {
+				fieldPath = path + "." + field.Name
+			}
+			if tag != "" {
+				check(v.Field(i), fieldPath, tag, errs)
+			}
+			walk(v.Field(i), fieldPath, errs, active)
+		}
+	}
+}
+
+// check runs every rule of tag against v.
+func check(v reflect.Value, path, tag string, errs *[]error) {
+	for _, spec := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(strings.TrimSpace(spec), "=")
+		if name == "" {
+			continue
+		}
+		rulesMu.RLock()
+		rule, ok := rules[name]
+		rulesMu.RUnlock()
+
+		var err error
+		switch {
+		case !ok:
+			err = fmt.Errorf("%w %q", ErrUnknownRule, name)
+		case name != "required" && v.IsZero():
+			continue
+		default:
+			err = rule(v, param)
+		}
+		if err != nil {
+			*errs = append(*errs, &FieldError{Path: path, Rule: name, Param: param, Err: err})
+		}
+	}
+}
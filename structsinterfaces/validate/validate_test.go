@@ -0,0 +1,177 @@
+package validate_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/salmomascarenhas/go-study-exercises/structsinterfaces/validate"
+)
+
+type Contact struct {
+	Email string `validate:"required,email"`
+	Phone string `validate:"e164"`
+}
+
+type Account struct {
+	Name     string `validate:"required,min=3,max=10"`
+	Owner    Contact
+	Backup   *Contact
+	Contacts []Contact `validate:"max=2"`
+	ByRole   map[string]Contact
+	Ignored  Contact `validate:"-"`
+	hidden   Contact
+}
+
+// violations returns "Path rule" for every FieldError in err, in order.
+func violations(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("got %T, want an errors.Join", err)
+	}
+	var got []string
+	for _, e := range joined.Unwrap() {
+		var fe *validate.FieldError
+		if !errors.As(e, &fe) {
+			t.Fatalf("got %T, want a *validate.FieldError", e)
+		}
+		got = append(got, fe.Path+" "+fe.Rule)
+	}
+	return got
+}
+
+func TestStructValid(t *testing.T) {
+	a := Account{
+		Name:     "salmo",
+		Owner:    Contact{Email: "a@example.com"},
+		Contacts: []Contact{{Email: "b@example.com", Phone: "+5511999999999"}},
+	}
+	if err := validate.Struct(&a); err != nil {
+		t.Errorf("valid account: %v", err)
+	}
+}
+
+func TestStructNested(t *testing.T) {
+	a := Account{
+		Name:   "ab",
+		Owner:  Contact{Email: "not an email", Phone: "123"},
+		Backup: &Contact{},
+		Contacts: []Contact{
+			{Email: "ok@example.com"},
+			{Email: "bad"},
+			{Email: "ok@example.com"},
+		},
+		ByRole: map[string]Contact{
+			"billing": {Email: "bad"},
+			"admin":   {},
+		},
+		Ignored: Contact{Email: "bad"},
+		hidden:  Contact{Email: "bad"},
+	}
+	want := []string{
+		"Name min",
+		"Owner.Email email",
+		"Owner.Phone e164",
+		"Backup.Email required",
+		"Contacts max",
+		"Contacts[1].Email email",
+		"ByRole[admin].Email required",
+		"ByRole[billing].Email email",
+	}
+	got := violations(t, validate.Struct(a))
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("violations:\ngot  %q\nwant %q", got, want)
+	}
+}
+
+func TestStructErrorMessage(t *testing.T) {
+	err := validate.Struct(Contact{Email: "bad"})
+	if want := "Email: must be a valid email address"; err == nil || err.Error() != want {
+		t.Errorf("got %v, want %q", err, want)
+	}
+}
+
+func TestStructNotAStruct(t *testing.T) {
+	for _, v := range []any{nil, (*Contact)(nil), 42, []Contact{}} {
+		if err := validate.Struct(v); err == nil {
+			t.Errorf("Struct(%#v) succeeded, want an error", v)
+		}
+	}
+}
+
+type Node struct {
+	Name     string `validate:"required"`
+	Next     *Node
+	Children []*Node
+}
+
+func TestStructCycle(t *testing.T) {
+	a := &Node{Name: "a"}
+	b := &Node{}
+	a.Next, b.Next = b, a
+	got := violations(t, validate.Struct(a))
+	if want := []string{"Next.Name required"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestStructSharedPointer(t *testing.T) {
+	// Two paths to the same node are not a cycle: both are reported.
+	shared := &Node{}
+	root := &Node{Name: "root", Children: []*Node{shared, shared}}
+	got := violations(t, validate.Struct(root))
+	want := []string{"Children[0].Name required", "Children[1].Name required"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+type Order struct {
+	Quantity int    `validate:"even"`
+	Code     string `validate:"nosuchrule"`
+}
+
+func TestRegister(t *testing.T) {
+	validate.Register("even", func(v reflect.Value, _ string) error {
+		if v.Int()%2 != 0 {
+			return fmt.Errorf("must be even, got %d", v.Int())
+		}
+		return nil
+	})
+	if !slices.Contains(validate.Rules(), "even") {
+		t.Errorf("Rules() = %v, want it to include even", validate.Rules())
+	}
+
+	err := validate.Struct(Order{Quantity: 3, Code: "x"})
+	got := violations(t, err)
+	if want := []string{"Quantity even", "Code nosuchrule"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if !errors.Is(err, validate.ErrUnknownRule) {
+		t.Errorf("got %v, want it to wrap ErrUnknownRule", err)
+	}
+
+	// Zero values skip every registered rule but required. A rule that
+	// doesn't exist is a mistake in the tag, reported anyway.
+	got = violations(t, validate.Struct(Order{}))
+	if want := []string{"Code nosuchrule"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("zero Order: got %q, want %q", got, want)
+	}
+
+	for _, name := range []string{"even", "email"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("registering %q twice didn't panic", name)
+				}
+			}()
+			validate.Register(name, func(reflect.Value, string) error { return nil })
+		}()
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/salmomascarenhas/go-study-exercises/structsinterfaces/validate"
+)
+
+func init() {
+	// "past" is a custom rule: birthdates can't be in the future.
+	validate.Register("past", func(v reflect.Value, _ string) error {
+		t, ok := v.Interface().(time.Time)
+		if !ok {
+			return errors.New("past rule only applies to time.Time")
+		}
+		if t.After(time.Now()) {
+			return errors.New("must be in the past")
+		}
+		return nil
+	})
+}
+
+// Validate checks p against its validate tags and returns every violation
+// at once, each one a *validate.FieldError.
+func (p Person) Validate() error {
+	return validate.Struct(p)
+}
@@ -87,3 +87,17 @@ package main
 // func print[T any](x T) {
 // 	fmt.Println(x)
 // }
+
+// Generic types are what make Result and Option possible: one definition
+// works for every T, and the compiler checks each use. See the result and
+// option packages, used by the errors exercise:
+//
+// r := result.Of(strconv.Atoi("42")) // Result[int]
+// s := result.Map(r, strconv.Itoa)   // Result[string]
+// n := result.FlatMap(s, func(s string) result.Result[int] {
+// 	return result.Of(strconv.Atoi(s + "x"))
+// })
+// fmt.Println(s, n.OrElse(-1)) // Ok(42) -1
+//
+// o := option.Of(os.LookupEnv("HOME")) // Option[string]
+// fmt.Println(option.Map(o, strings.ToUpper).OrElse("?"))
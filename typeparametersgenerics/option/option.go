@@ -0,0 +1,76 @@
+// Package option provides Option, a value that may be absent, as a type
+// instead of the (value, ok) pair Go uses for map lookups and type
+// assertions.
+package option
+
+import "fmt"
+
+// Option holds a value of type T, or nothing. The zero value is None.
+type Option[T any] struct {
+	value T
+	ok    bool
+}
+
+func Some[T any](v T) Option[T] {
+	return Option[T]{value: v, ok: true}
+}
+
+func None[T any]() Option[T] {
+	return Option[T]{}
+}
+
+// Of converts the (value, ok) idiom of map lookups and type assertions: after
+// v, ok := m[k], Of(v, ok) is Some when ok.
+func Of[T any](v T, ok bool) Option[T] {
+	if !ok {
+		return None[T]()
+	}
+	return Some(v)
+}
+
+// Get converts o back to the (value, ok) idiom.
+func (o Option[T]) Get() (T, bool) {
+	return o.value, o.ok
+}
+
+func (o Option[T]) IsSome() bool { return o.ok }
+
+// OrElse returns the value of o, or v if o is None.
+func (o Option[T]) OrElse(v T) T {
+	if !o.ok {
+		return v
+	}
+	return o.value
+}
+
+// Unwrap returns the value of o and panics if o is None. Use it only where
+// None is a bug.
+func (o Option[T]) Unwrap() T {
+	if !o.ok {
+		panic("option: Unwrap of None")
+	}
+	return o.value
+}
+
+func (o Option[T]) String() string {
+	if !o.ok {
+		return "None"
+	}
+	return fmt.Sprintf("Some(%v)", o.value)
+}
+
+// Map applies f to the value of o. f is not called if o is None.
+func Map[T, U any](o Option[T], f func(T) U) Option[U] {
+	if !o.ok {
+		return None[U]()
+	}
+	return Some(f(o.value))
+}
+
+// FlatMap is Map for functions that may return None themselves.
+func FlatMap[T, U any](o Option[T], f func(T) Option[U]) Option[U] {
+	if !o.ok {
+		return None[U]()
+	}
+	return f(o.value)
+}
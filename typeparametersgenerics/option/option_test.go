@@ -0,0 +1,94 @@
+package option_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/salmomascarenhas/go-study-exercises/typeparametersgenerics/option"
+)
+
+func TestOfGet(t *testing.T) {
+	m := map[string]int{"a": 1}
+	lookup := func(k string) option.Option[int] {
+		v, ok := m[k]
+		return option.Of(v, ok)
+	}
+
+	v, ok := lookup("a").Get()
+	if v != 1 || !ok {
+		t.Errorf("lookup(a).Get() = %v, %v; want 1, true", v, ok)
+	}
+	v, ok = lookup("b").Get()
+	if v != 0 || ok {
+		t.Errorf("lookup(b).Get() = %v, %v; want 0, false", v, ok)
+	}
+
+	var x any = "s"
+	if n, ok := x.(int); option.Of(n, ok).IsSome() {
+		t.Errorf("Of of a failed type assertion is Some")
+	}
+
+	var zero option.Option[int]
+	if zero.IsSome() || zero != option.None[int]() {
+		t.Errorf("the zero Option is %v, want None", zero)
+	}
+}
+
+func TestOrElse(t *testing.T) {
+	if got := option.Some(3).OrElse(7); got != 3 {
+		t.Errorf("Some(3).OrElse(7) = %d, want 3", got)
+	}
+	if got := option.None[int]().OrElse(7); got != 7 {
+		t.Errorf("None.OrElse(7) = %d, want 7", got)
+	}
+}
+
+func TestUnwrap(t *testing.T) {
+	if got := option.Some("x").Unwrap(); got != "x" {
+		t.Errorf("Some(x).Unwrap() = %q, want x", got)
+	}
+	defer func() {
+		if r := recover(); r != "option: Unwrap of None" {
+			t.Errorf("None.Unwrap() panicked with %v", r)
+		}
+	}()
+	option.None[string]().Unwrap()
+	t.Error("None.Unwrap() didn't panic")
+}
+
+func TestString(t *testing.T) {
+	if got := option.Some(3).String(); got != "Some(3)" {
+		t.Errorf("got %q, want Some(3)", got)
+	}
+	if got := option.None[int]().String(); got != "None" {
+		t.Errorf("got %q, want None", got)
+	}
+}
+
+func TestMapFlatMap(t *testing.T) {
+	calls := 0
+	double := func(x int) int { calls++; return 2 * x }
+	if got := option.Map(option.Some(3), double); got != option.Some(6) {
+		t.Errorf("Map(Some(3)) = %v, want Some(6)", got)
+	}
+	if got := option.Map(option.None[int](), double); got.IsSome() {
+		t.Errorf("Map(None) = %v, want None", got)
+	}
+	if calls != 1 {
+		t.Errorf("f was called %d times, want 1: not for None", calls)
+	}
+
+	atoi := func(s string) option.Option[int] {
+		n, err := strconv.Atoi(s)
+		return option.Of(n, err == nil)
+	}
+	if got := option.FlatMap(option.Some("42"), atoi); got != option.Some(42) {
+		t.Errorf("FlatMap(Some(42)) = %v, want Some(42)", got)
+	}
+	if got := option.FlatMap(option.Some("x"), atoi); got.IsSome() {
+		t.Errorf("FlatMap(Some(x)) = %v, want None", got)
+	}
+	if got := option.FlatMap(option.None[string](), atoi); got.IsSome() {
+		t.Errorf("FlatMap(None) = %v, want None", got)
+	}
+}
@@ -0,0 +1,113 @@
+// Package result provides Result, a value or the error that prevented it,
+// as a single type instead of Go's (value, error) pair, so that calls can be
+// chained with Map and FlatMap and the first error skips the rest.
+//
+// Of and Get convert from and to (value, error), so Result stays at the
+// edges of a chain and ordinary Go code never has to see it.
+//
+// Go methods can't have type parameters of their own, so Map and FlatMap,
+// here and in package option, are functions rather than methods.
+package result
+
+import (
+	"fmt"
+
+	"github.com/salmomascarenhas/go-study-exercises/typeparametersgenerics/option"
+)
+
+// Result holds a value of type T, or a non-nil error. The zero value is
+// Ok with the zero T.
+type Result[T any] struct {
+	value T
+	err   error
+}
+
+func Ok[T any](v T) Result[T] {
+	return Result[T]{value: v}
+}
+
+// Err returns a failed Result. A nil err is treated as Ok with the zero T,
+// as (zero, nil) would be.
+func Err[T any](err error) Result[T] {
+	return Result[T]{err: err}
+}
+
+// Of converts the (value, error) idiom, so Of(f()) works for any f
+// returning (T, error). The value is dropped if err is not nil.
+func Of[T any](v T, err error) Result[T] {
+	if err != nil {
+		return Err[T](err)
+	}
+	return Ok(v)
+}
+
+// Get converts r back to the (value, error) idiom. The error is the one r
+// was built with, so errors.Is and errors.As see everything it wraps.
+func (r Result[T]) Get() (T, error) {
+	return r.value, r.err
+}
+
+func (r Result[T]) IsOk() bool { return r.err == nil }
+
+// Err returns the error of r, or nil if r is Ok.
+func (r Result[T]) Err() error { return r.err }
+
+// OrElse returns the value of r, or v if r failed.
+func (r Result[T]) OrElse(v T) T {
+	if r.err != nil {
+		return v
+	}
+	return r.value
+}
+
+// Unwrap returns the value of r and panics with its error if r failed. Use
+// it only where an error is a bug. It is unrelated to the Unwrap method of
+// errors: a Result is not an error.
+func (r Result[T]) Unwrap() T {
+	if r.err != nil {
+		panic(r.err)
+	}
+	return r.value
+}
+
+func (r Result[T]) String() string {
+	if r.err != nil {
+		return fmt.Sprintf("Err(%v)", r.err)
+	}
+	return fmt.Sprintf("Ok(%v)", r.value)
+}
+
+// Map applies f to the value of r. A failed r is passed along unchanged and
+// f is not called.
+func Map[T, U any](r Result[T], f func(T) U) Result[U] {
+	if r.err != nil {
+		return Err[U](r.err)
+	}
+	return Ok(f(r.value))
+}
+
+// FlatMap is Map for functions that may fail themselves.
+func FlatMap[T, U any](r Result[T], f func(T) Result[U]) Result[U] {
+	if r.err != nil {
+		return Err[U](r.err)
+	}
+	return f(r.value)
+}
+
+// FromOption returns the value of o, or err if o is None. It panics if err
+// is nil, even when o has a value: Err would turn a nil err into Ok with
+// the zero T, and a missing value would pass for a success.
+func FromOption[T any](o option.Option[T], err error) Result[T] {
+	if err == nil {
+		panic("result: FromOption with a nil error")
+	}
+	if v, ok := o.Get(); ok {
+		return Ok(v)
+	}
+	return Err[T](err)
+}
+
+// ToOption returns the value of r, dropping its error.
+func ToOption[T any](r Result[T]) option.Option[T] {
+	return option.Of(r.value, r.err == nil)
+}
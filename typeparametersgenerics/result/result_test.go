@@ -0,0 +1,134 @@
+package result_test
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/salmomascarenhas/go-study-exercises/typeparametersgenerics/option"
+	"github.com/salmomascarenhas/go-study-exercises/typeparametersgenerics/result"
+)
+
+var errBoom = errors.New("boom")
+
+func TestOfGet(t *testing.T) {
+	v, err := result.Of(strconv.Atoi("42")).Get()
+	if v != 42 || err != nil {
+		t.Errorf("Of(Atoi(42)).Get() = %v, %v; want 42, nil", v, err)
+	}
+
+	// The value is dropped with the error, and the error is kept as is.
+	wrapped := fmt.Errorf("loading: %w", errBoom)
+	v, err = result.Of(7, wrapped).Get()
+	if v != 0 || !errors.Is(err, errBoom) || err.Error() != wrapped.Error() {
+		t.Errorf("Of(7, err).Get() = %v, %v; want 0, %v", v, err, wrapped)
+	}
+
+	if r := result.Err[int](nil); !r.IsOk() || r.Unwrap() != 0 {
+		t.Errorf("Err(nil) = %v, want Ok(0)", r)
+	}
+	var zero result.Result[int]
+	if !zero.IsOk() {
+		t.Errorf("the zero Result is %v, want Ok(0)", zero)
+	}
+}
+
+func TestOrElse(t *testing.T) {
+	if got := result.Ok(3).OrElse(7); got != 3 {
+		t.Errorf("Ok(3).OrElse(7) = %d, want 3", got)
+	}
+	if got := result.Err[int](errBoom).OrElse(7); got != 7 {
+		t.Errorf("Err.OrElse(7) = %d, want 7", got)
+	}
+}
+
+func TestUnwrap(t *testing.T) {
+	if got := result.Ok("x").Unwrap(); got != "x" {
+		t.Errorf("Ok(x).Unwrap() = %q, want x", got)
+	}
+	defer func() {
+		err, _ := recover().(error)
+		if !errors.Is(err, errBoom) {
+			t.Errorf("Err.Unwrap() panicked with %v, want errBoom", err)
+		}
+	}()
+	result.Err[string](errBoom).Unwrap()
+	t.Error("Err.Unwrap() didn't panic")
+}
+
+func TestString(t *testing.T) {
+	if got := result.Ok(3).String(); got != "Ok(3)" {
+		t.Errorf("got %q, want Ok(3)", got)
+	}
+	if got := result.Err[int](errBoom).String(); got != "Err(boom)" {
+		t.Errorf("got %q, want Err(boom)", got)
+	}
+}
+
+func TestMapFlatMapSkipAfterError(t *testing.T) {
+	calls := 0
+	double := func(x int) int { calls++; return 2 * x }
+	atoi := func(s string) result.Result[int] { calls++; return result.Of(strconv.Atoi(s)) }
+
+	if got := result.Map(result.FlatMap(result.Ok("21"), atoi), double); got.Unwrap() != 42 {
+		t.Errorf("Ok chain = %v, want Ok(42)", got)
+	}
+	if calls != 2 {
+		t.Fatalf("Ok chain made %d calls, want 2", calls)
+	}
+
+	// The first failure is carried to the end; nothing after it runs.
+	calls = 0
+	got := result.Map(result.Map(result.FlatMap(result.Ok("x"), atoi), double), double)
+	var numErr *strconv.NumError
+	if got.IsOk() || !errors.As(got.Err(), &numErr) {
+		t.Errorf("failing chain = %v, want the *strconv.NumError", got)
+	}
+	if calls != 1 {
+		t.Errorf("failing chain made %d calls, want 1: only atoi", calls)
+	}
+
+	calls = 0
+	result.FlatMap(result.Err[string](errBoom), atoi)
+	result.Map(result.Err[int](errBoom), double)
+	if calls != 0 {
+		t.Errorf("f was called %d times on a failed Result, want 0", calls)
+	}
+}
+
+func TestOption(t *testing.T) {
+	if got := result.ToOption(result.Ok(3)); got != option.Some(3) {
+		t.Errorf("ToOption(Ok(3)) = %v, want Some(3)", got)
+	}
+	if got := result.ToOption(result.Err[int](errBoom)); got.IsSome() {
+		t.Errorf("ToOption(Err) = %v, want None", got)
+	}
+
+	if got := result.FromOption(option.Some(3), errBoom); got.Unwrap() != 3 {
+		t.Errorf("FromOption(Some(3)) = %v, want Ok(3)", got)
+	}
+	if got := result.FromOption(option.None[int](), errBoom); !errors.Is(got.Err(), errBoom) {
+		t.Errorf("FromOption(None) = %v, want Err(boom)", got)
+	}
+
+	// Round trip: Ok survives both ways, the error is replaced.
+	back := result.FromOption(result.ToOption(result.Err[int](errBoom)), errors.ErrUnsupported)
+	if !errors.Is(back.Err(), errors.ErrUnsupported) {
+		t.Errorf("Err round trip = %v, want the error given to FromOption", back)
+	}
+}
+
+func TestFromOptionNilError(t *testing.T) {
+	for name, o := range map[string]option.Option[int]{"None": option.None[int](), "Some": option.Some(3)} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != "result: FromOption with a nil error" {
+					t.Errorf("FromOption(%s, nil) panicked with %v", name, r)
+				}
+			}()
+			got := result.FromOption(o, nil)
+			t.Errorf("FromOption(%s, nil) = %v, want a panic", name, got)
+		})
+	}
+}